@@ -2,12 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"agent-service/internal/config"
+	"agent-service/internal/controllerclient"
+	"agent-service/internal/controllerpb"
+	"agent-service/internal/repository"
+	"agent-service/internal/repository/local"
 	"agent-service/internal/repository/redis"
 	"agent-service/internal/service"
+	"agent-service/internal/tlsutil"
 
 	"github.com/joho/godotenv"
 )
@@ -19,27 +29,95 @@ func main() {
 
 	cfg := config.Load()
 
-	cache := redis.NewRedisHelper(redis.RedisConfig{
-		Addr:     cfg.RedisAddr,
-		Password: cfg.RedisPassword,
-		DB:       cfg.RedisDB,
-	})
+	tlsClient, err := tlsutil.NewClient(cfg.TLSCAFile, cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		log.Fatal("failed to load TLS config:", err)
+	}
+
+	// a controller requiring client certificates registers the agent under
+	// the certificate's name, so default to it
+	if cfg.AgentName == "" && cfg.TLSCertFile != "" {
+		cfg.AgentName, err = tlsutil.KeyPairIdentity(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			log.Fatal("failed to read client certificate:", err)
+		}
+	}
+
+	var cache repository.ICache
+	switch cfg.CacheBackend {
+	case "memory":
+		cache = local.NewMemoryCache()
+	case "file":
+		cache, err = local.NewFileCache(cfg.CacheFile)
+		if err != nil {
+			log.Fatal("failed to load cache file:", err)
+		}
+	default:
+		cache = redis.NewRedisHelper(redis.RedisConfig{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+	}
+
+	// ctx is done on SIGINT or SIGTERM, stopping the poller
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	ctx := context.Background()
 	if err := cache.Ping(ctx); err != nil {
-		log.Fatal("failed to ping redis:", err)
+		log.Fatal("failed to ping cache:", err)
 	}
 
-	slog.Info("Redis connection successful")
+	slog.Info("Cache connection successful", slog.String("backend", cfg.CacheBackend))
 
-	agentService := service.NewAgentService(
-		cfg.ControllerURL,
-		cfg.WorkerURL,
-		cfg.APIKey,
-		cache,
-	)
+	var controllerGRPC controllerpb.ControllerServiceClient
+	if cfg.ControllerProtocol == "grpc" {
+		client, err := controllerclient.New(cfg.ControllerGRPCAddr, tlsClient.Config, cfg.APIKey)
+		if err != nil {
+			log.Fatal("failed to create controller gRPC client:", err)
+		}
+		defer client.Close()
+
+		controllerGRPC = client
+	}
 
-	if err := agentService.RegisterAgent(ctx); err != nil {
+	agentService := service.NewAgentService(service.AgentConfig{
+		ControllerURL: cfg.ControllerURL,
+		AgentName:     cfg.AgentName,
+		Namespace:     cfg.Namespace,
+		Labels:        cfg.Labels,
+		WorkerURLs:    cfg.WorkerURLs,
+		APIKey:        cfg.APIKey,
+		WorkerAPIKey:  cfg.WorkerAPIKey,
+		SyncMode:      cfg.SyncMode,
+		Transport:     tlsClient.Transport(),
+
+		WorkerDiscoveryFile: cfg.WorkerDiscoveryFile,
+		SnapshotFile:        cfg.SnapshotFile,
+		ControllerGRPC:      controllerGRPC,
+	}, cache)
+
+	if cfg.StatusAddr != "" {
+		statusServer := &http.Server{
+			Addr:    cfg.StatusAddr,
+			Handler: service.NewStatusHandler(agentService),
+		}
+		defer statusServer.Close()
+
+		go func() {
+			slog.Info("Starting status server at " + cfg.StatusAddr)
+			if err := statusServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal("failed to serve status:", err)
+			}
+		}()
+	}
+
+	if err := agentService.RegisterAgent(ctx); err != nil && ctx.Err() == nil {
 		log.Fatal(err)
 	}
+
+	if err := cache.Close(); err != nil {
+		slog.Error("failed to close cache", slog.Any("error", err))
+	}
+	slog.Info("Shutdown complete")
 }
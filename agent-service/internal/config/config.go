@@ -4,12 +4,44 @@ import (
 	"log/slog"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
 	ControllerURL string
+	AgentName     string
+	Namespace     string
+	Labels        map[string]string
 	APIKey        string
-	WorkerURL     string
+	WorkerURLs    []string
+	WorkerAPIKey  string
+	SyncMode      string
+
+	// WorkerDiscoveryFile lists more worker URLs, one per line, next to
+	// the static WORKER_URLS
+	WorkerDiscoveryFile string
+
+	// SnapshotFile keeps the last-known-good config next to the cache;
+	// StatusAddr serves the agent status, e.g. whether it is degraded
+	SnapshotFile string
+	StatusAddr   string
+
+	// ControllerProtocol is "http" (default) to use the controller's REST
+	// API at ControllerURL, or "grpc" to use its gRPC API at
+	// ControllerGRPCAddr
+	ControllerProtocol string
+	ControllerGRPCAddr string
+
+	// TLSCAFile verifies the controller and worker certificates;
+	// TLSCertFile and TLSKeyFile are presented as client certificate
+	TLSCAFile   string
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// CacheBackend is "redis" (default), "memory" or "file"; the file
+	// backend keeps the cache in CacheFile
+	CacheBackend string
+	CacheFile    string
 
 	RedisAddr     string
 	RedisPassword string
@@ -31,12 +63,104 @@ func Load() Config {
 		}
 	}
 
+	syncMode := os.Getenv("CONFIG_SYNC_MODE")
+	switch syncMode {
+	case "watch", "poll", "stream":
+	case "":
+		syncMode = "watch"
+	default:
+		slog.Info("Invalid CONFIG_SYNC_MODE value, using default of watch", slog.String("CONFIG_SYNC_MODE", syncMode))
+		syncMode = "watch"
+	}
+
+	cacheBackend := os.Getenv("CACHE_BACKEND")
+	switch cacheBackend {
+	case "redis", "memory", "file":
+	case "":
+		cacheBackend = "redis"
+	default:
+		slog.Info("Invalid CACHE_BACKEND value, using default of redis", slog.String("CACHE_BACKEND", cacheBackend))
+		cacheBackend = "redis"
+	}
+
+	cacheFile := os.Getenv("CACHE_FILE")
+	if cacheFile == "" {
+		cacheFile = "agent-cache.json"
+	}
+
+	controllerProtocol := os.Getenv("CONTROLLER_PROTOCOL")
+	switch controllerProtocol {
+	case "http", "grpc":
+	case "":
+		controllerProtocol = "http"
+	default:
+		slog.Info("Invalid CONTROLLER_PROTOCOL value, using default of http", slog.String("CONTROLLER_PROTOCOL", controllerProtocol))
+		controllerProtocol = "http"
+	}
+
+	// a dedicated worker key keeps the controller key (scoped to agents) and
+	// the worker key apart; deployments with one shared key keep working
+	workerAPIKey := os.Getenv("WORKER_API_KEY")
+	if workerAPIKey == "" {
+		workerAPIKey = os.Getenv("API_KEY")
+	}
+
 	return Config{
 		ControllerURL: os.Getenv("CONTROLLER_URL"),
+		AgentName:     os.Getenv("AGENT_NAME"),
+		Namespace:     os.Getenv("AGENT_NAMESPACE"),
+		Labels:        parseLabels(os.Getenv("AGENT_LABELS")),
 		APIKey:        os.Getenv("API_KEY"),
-		WorkerURL:     os.Getenv("WORKER_URL"),
+		WorkerURLs:    parseList(os.Getenv("WORKER_URL") + "," + os.Getenv("WORKER_URLS")),
+		WorkerAPIKey:  workerAPIKey,
+		SyncMode:      syncMode,
+
+		WorkerDiscoveryFile: os.Getenv("WORKER_DISCOVERY_FILE"),
+
+		SnapshotFile: os.Getenv("CONFIG_SNAPSHOT_FILE"),
+		StatusAddr:   os.Getenv("STATUS_ADDR"),
+
+		ControllerProtocol: controllerProtocol,
+		ControllerGRPCAddr: os.Getenv("CONTROLLER_GRPC_ADDR"),
+
+		TLSCAFile:     os.Getenv("TLS_CA_FILE"),
+		TLSCertFile:   os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:    os.Getenv("TLS_KEY_FILE"),
+		CacheBackend:  cacheBackend,
+		CacheFile:     cacheFile,
 		RedisAddr:     os.Getenv("REDIS_ADDR"),
 		RedisPassword: os.Getenv("REDIS_PASSWORD"),
 		RedisDB:       redisDB,
 	}
 }
+
+// parseLabels parses comma-separated key=value pairs, e.g. "region=eu,tier=canary".
+func parseLabels(value string) map[string]string {
+	labels := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			slog.Info("Invalid AGENT_LABELS entry, ignoring it", slog.String("entry", pair))
+			continue
+		}
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return labels
+}
+
+// parseList parses a comma-separated list, skipping empty entries.
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
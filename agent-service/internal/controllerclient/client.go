@@ -0,0 +1,74 @@
+// Package controllerclient is a client of the controller's gRPC API,
+// ControllerService, authenticating with an API key over TLS.
+package controllerclient
+
+//go:generate protoc --proto_path=../../../controller-service/proto --go_out=../.. --go_opt=module=agent-service,Mcontroller/v1/controller.proto=agent-service/internal/controllerpb --go-grpc_out=../.. --go-grpc_opt=module=agent-service,Mcontroller/v1/controller.proto=agent-service/internal/controllerpb controller/v1/controller.proto
+
+import (
+	"agent-service/internal/controllerpb"
+	"context"
+	"crypto/tls"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+type Client struct {
+	controllerpb.ControllerServiceClient
+	conn *grpc.ClientConn
+}
+
+// New connects to the controller's gRPC API at addr (host:port). tlsConfig
+// returns the TLS config of a connection to a server name, so certificates
+// can change between connections; apiKey is sent with every call.
+func New(addr string, tlsConfig func(serverName string) *tls.Config, apiKey string) (*Client, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(&reloadingTLS{
+			TransportCredentials: credentials.NewTLS(tlsConfig("")),
+			config:               tlsConfig,
+		}),
+		grpc.WithPerRPCCredentials(apiKeyCredentials(apiKey)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		ControllerServiceClient: controllerpb.NewControllerServiceClient(conn),
+		conn:                    conn,
+	}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// reloadingTLS performs every client handshake with a fresh TLS config.
+type reloadingTLS struct {
+	credentials.TransportCredentials
+	config func(serverName string) *tls.Config
+}
+
+func (c *reloadingTLS) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	serverName, _, err := net.SplitHostPort(authority)
+	if err != nil {
+		serverName = authority
+	}
+	return credentials.NewTLS(c.config(serverName)).ClientHandshake(ctx, authority, conn)
+}
+
+func (c *reloadingTLS) Clone() credentials.TransportCredentials {
+	return &reloadingTLS{TransportCredentials: c.TransportCredentials.Clone(), config: c.config}
+}
+
+// apiKeyCredentials sends the API key in the x-api-key metadata.
+type apiKeyCredentials string
+
+func (k apiKeyCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"x-api-key": string(k)}, nil
+}
+
+func (k apiKeyCredentials) RequireTransportSecurity() bool {
+	return true
+}
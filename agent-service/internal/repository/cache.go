@@ -2,11 +2,16 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
-
-	"github.com/redis/go-redis/v9"
 )
 
+// ErrKeyNotFound is returned by GetKey when the key does not exist.
+var ErrKeyNotFound = errors.New("key not found")
+
+// ICache is the agent's key-value store: Redis, or a local cache in memory or
+// in a file for deployments without Redis.
+//
 //go:generate mockgen -destination=mocks/mock_cache.go -source=cache.go ICache
 type ICache interface {
 	Ping(ctx context.Context) error
@@ -15,8 +20,7 @@ type ICache interface {
 	GetKey(ctx context.Context, key string) (string, error)
 	DeleteKey(ctx context.Context, key string) error
 	SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error)
-	PubSubConn(ctx context.Context, key string) *redis.PubSub
-	Publish(ctx context.Context, key, message string) error
 	Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
 	Release(ctx context.Context, key string) error
+	Close() error
 }
@@ -0,0 +1,173 @@
+// Package local implements the agent cache inside the agent process, in
+// memory only or persisted to a JSON file, for deployments without Redis.
+package local
+
+import (
+	"agent-service/internal/fileutil"
+	"agent-service/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	Value     string     `json:"value"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (e entry) expired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// Cache is a key-value cache with expiring keys. With a path every change is
+// written to the file, replaced atomically, and the cache survives restarts.
+type Cache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]entry
+}
+
+// NewMemoryCache returns a cache that lives as long as the agent process.
+func NewMemoryCache() *Cache {
+	return &Cache{entries: map[string]entry{}}
+}
+
+// NewFileCache returns a cache persisted to path, loading the entries a
+// previous run left there.
+func NewFileCache(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: map[string]entry{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for key, e := range c.entries {
+		if e.expired(now) {
+			delete(c.entries, key)
+		}
+	}
+
+	return c, nil
+}
+
+// Ping checks that the cache file can be written.
+func (c *Cache) Ping(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.persist()
+}
+
+func (c *Cache) SetKey(ctx context.Context, key string, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry{Value: value}
+	return c.persist()
+}
+
+func (c *Cache) SetKeyWithExpire(ctx context.Context, key string, value string, expireInSecond time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = newEntry(value, expireInSecond)
+	return c.persist()
+}
+
+func (c *Cache) GetKey(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.get(key)
+	if !ok {
+		return "", repository.ErrKeyNotFound
+	}
+	return e.Value, nil
+}
+
+func (c *Cache) DeleteKey(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	return c.persist()
+}
+
+func (c *Cache) SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.get(key); ok {
+		return false, nil
+	}
+
+	c.entries[key] = newEntry("lock", ttl)
+	if err := c.persist(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Cache) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return c.SetNX(ctx, key, ttl)
+}
+
+func (c *Cache) Release(ctx context.Context, key string) error {
+	return c.DeleteKey(ctx, key)
+}
+
+// Close does nothing; every change is already written.
+func (c *Cache) Close() error {
+	return nil
+}
+
+// get returns the entry of key unless it expired, dropping expired entries.
+func (c *Cache) get(key string) (entry, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return e, false
+	}
+	if e.expired(time.Now()) {
+		delete(c.entries, key)
+		return e, false
+	}
+	return e, true
+}
+
+// persist writes the entries to the cache file, if any. c.mu must be held.
+func (c *Cache) persist() error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+
+	return fileutil.WriteAtomic(c.path, data)
+}
+
+// newEntry returns an entry expiring after ttl, or never when ttl is zero,
+// like Redis.
+func newEntry(value string, ttl time.Duration) entry {
+	e := entry{Value: value}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		e.ExpiresAt = &expiresAt
+	}
+	return e
+}
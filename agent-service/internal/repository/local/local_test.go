@@ -0,0 +1,128 @@
+package local
+
+import (
+	"agent-service/internal/repository"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileCacheReload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache.json")
+
+	cache, err := NewFileCache(path)
+	if err != nil {
+		t.Fatalf("NewFileCache() error = %v", err)
+	}
+	if err := cache.SetKey(ctx, "config_agent:1", `{"version":3}`); err != nil {
+		t.Fatalf("SetKey() error = %v", err)
+	}
+	if err := cache.SetKey(ctx, "deleted", "value"); err != nil {
+		t.Fatalf("SetKey() error = %v", err)
+	}
+	if err := cache.DeleteKey(ctx, "deleted"); err != nil {
+		t.Fatalf("DeleteKey() error = %v", err)
+	}
+	if err := cache.SetKeyWithExpire(ctx, "lock", "lock", time.Hour); err != nil {
+		t.Fatalf("SetKeyWithExpire() error = %v", err)
+	}
+
+	// every write renames a complete file into place
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "cache.json" {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Fatalf("cache dir holds %v, want only cache.json", names)
+	}
+
+	reopened, err := NewFileCache(path)
+	if err != nil {
+		t.Fatalf("NewFileCache() after restart error = %v", err)
+	}
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr error
+	}{
+		{key: "config_agent:1", want: `{"version":3}`},
+		{key: "lock", want: "lock"},
+		{key: "deleted", wantErr: repository.ErrKeyNotFound},
+	}
+	for _, tt := range tests {
+		got, err := reopened.GetKey(ctx, tt.key)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("GetKey(%q) = %q, %v, want %q, %v", tt.key, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFileCacheDropsExpiredEntries(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	// a lock left behind by a previous run that has expired since
+	expired := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano)
+	data := `{"lock":{"value":"lock","expires_at":"` + expired + `"},"kept":{"value":"value"}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cache, err := NewFileCache(path)
+	if err != nil {
+		t.Fatalf("NewFileCache() error = %v", err)
+	}
+
+	if _, err := cache.GetKey(ctx, "lock"); !errors.Is(err, repository.ErrKeyNotFound) {
+		t.Errorf("GetKey(expired) error = %v, want %v", err, repository.ErrKeyNotFound)
+	}
+	if got, err := cache.GetKey(ctx, "kept"); err != nil || got != "value" {
+		t.Errorf("GetKey(kept) = %q, %v, want %q", got, err, "value")
+	}
+
+	acquired, err := cache.SetNX(ctx, "lock", time.Hour)
+	if err != nil || !acquired {
+		t.Errorf("SetNX() on an expired lock = %v, %v, want true", acquired, err)
+	}
+}
+
+func TestSetNX(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache()
+
+	tests := []struct {
+		name string
+		run  func() (bool, error)
+		want bool
+	}{
+		{name: "free key", run: func() (bool, error) { return cache.SetNX(ctx, "lock", time.Hour) }, want: true},
+		{name: "held key", run: func() (bool, error) { return cache.Acquire(ctx, "lock", time.Hour) }, want: false},
+		{name: "released key", run: func() (bool, error) {
+			if err := cache.Release(ctx, "lock"); err != nil {
+				return false, err
+			}
+			return cache.Acquire(ctx, "lock", time.Hour)
+		}, want: true},
+	}
+
+	// the steps run in order against the same cache
+	for _, tt := range tests {
+		got, err := tt.run()
+		if err != nil {
+			t.Fatalf("%s: error = %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: acquired = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cache.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockICache is a mock of ICache interface.
+type MockICache struct {
+	ctrl     *gomock.Controller
+	recorder *MockICacheMockRecorder
+}
+
+// MockICacheMockRecorder is the mock recorder for MockICache.
+type MockICacheMockRecorder struct {
+	mock *MockICache
+}
+
+// NewMockICache creates a new mock instance.
+func NewMockICache(ctrl *gomock.Controller) *MockICache {
+	mock := &MockICache{ctrl: ctrl}
+	mock.recorder = &MockICacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICache) EXPECT() *MockICacheMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockICache) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx, key, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockICacheMockRecorder) Acquire(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockICache)(nil).Acquire), ctx, key, ttl)
+}
+
+// Close mocks base method.
+func (m *MockICache) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockICacheMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockICache)(nil).Close))
+}
+
+// DeleteKey mocks base method.
+func (m *MockICache) DeleteKey(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKey indicates an expected call of DeleteKey.
+func (mr *MockICacheMockRecorder) DeleteKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKey", reflect.TypeOf((*MockICache)(nil).DeleteKey), ctx, key)
+}
+
+// GetKey mocks base method.
+func (m *MockICache) GetKey(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKey", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKey indicates an expected call of GetKey.
+func (mr *MockICacheMockRecorder) GetKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKey", reflect.TypeOf((*MockICache)(nil).GetKey), ctx, key)
+}
+
+// Ping mocks base method.
+func (m *MockICache) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockICacheMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockICache)(nil).Ping), ctx)
+}
+
+// Release mocks base method.
+func (m *MockICache) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockICacheMockRecorder) Release(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockICache)(nil).Release), ctx, key)
+}
+
+// SetKey mocks base method.
+func (m *MockICache) SetKey(ctx context.Context, key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKey", ctx, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetKey indicates an expected call of SetKey.
+func (mr *MockICacheMockRecorder) SetKey(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKey", reflect.TypeOf((*MockICache)(nil).SetKey), ctx, key, value)
+}
+
+// SetKeyWithExpire mocks base method.
+func (m *MockICache) SetKeyWithExpire(ctx context.Context, key, value string, expireInSecond time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKeyWithExpire", ctx, key, value, expireInSecond)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetKeyWithExpire indicates an expected call of SetKeyWithExpire.
+func (mr *MockICacheMockRecorder) SetKeyWithExpire(ctx, key, value, expireInSecond interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyWithExpire", reflect.TypeOf((*MockICache)(nil).SetKeyWithExpire), ctx, key, value, expireInSecond)
+}
+
+// SetNX mocks base method.
+func (m *MockICache) SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNX indicates an expected call of SetNX.
+func (mr *MockICacheMockRecorder) SetNX(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockICache)(nil).SetNX), ctx, key, ttl)
+}
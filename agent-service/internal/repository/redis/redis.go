@@ -1,7 +1,9 @@
 package redis
 
 import (
+	"agent-service/internal/repository"
 	"context"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -54,6 +56,9 @@ func (r *Redis) SetKeyWithExpire(ctx context.Context, key string, value string,
 
 func (r *Redis) GetKey(ctx context.Context, key string) (string, error) {
 	val, err := r.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", repository.ErrKeyNotFound
+	}
 	if err != nil {
 		return "", err
 	}
@@ -93,3 +98,7 @@ func (r *Redis) Release(ctx context.Context, key string) error {
 	_, err := r.client.Del(ctx, key).Result()
 	return err
 }
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
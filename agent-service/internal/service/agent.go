@@ -1,115 +1,233 @@
 package service
 
 import (
+	"agent-service/internal/controllerpb"
 	"agent-service/internal/repository"
-	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"math/rand"
 	"net/http"
-	"strconv"
+	"sync"
 	"time"
 )
 
+const (
+	// SyncModeWatch long-polls the controller and falls back to interval
+	// polling when the watch request fails.
+	SyncModeWatch = "watch"
+	// SyncModePoll only polls the controller every poll interval.
+	SyncModePoll = "poll"
+	// SyncModeStream keeps a config stream open to the controller and falls
+	// back to interval polling while it is broken.
+	SyncModeStream = "stream"
+)
+
+// defaultPoolingInterval is the pooling interval, in seconds, used when the
+// controller sends none.
+const defaultPoolingInterval = 5
+
 type AgentService struct {
-	controllerURL   string
-	workerURL       string
-	apiKey          string
+	controller      controllerAPI
+	agentName       string
+	namespace       string
+	labels          map[string]string
+	workers         workerPool
+	workerAPIKey    string
+	syncMode        string
 	cache           repository.ICache
 	agentID         string
 	poolingInterval int
 	httpClient      *http.Client
+	buildInfo       map[string]string
+	state           agentState
+
+	// snapshotFile, when set, keeps the last config all workers applied,
+	// booted from when neither the controller nor the cache has one
+	snapshotFile    string
+	snapshotVersion int
+
+	// syncMu serializes config pushes, so a worker catch-up never sends a
+	// stale version over a newer one
+	syncMu sync.Mutex
+}
+
+type AgentConfig struct {
+	ControllerURL string
+	AgentName     string // overrides the persisted or generated agent name
+	Namespace     string
+	Labels        map[string]string // matched by rollout selectors on the controller
+	WorkerURLs    []string
+	APIKey        string // sent to the controller
+	WorkerAPIKey  string // sent to the worker
+	SyncMode      string
+	Transport     *http.Transport // TLS settings towards the controller and the workers
+
+	// WorkerDiscoveryFile, when set, lists more worker URLs, one per line;
+	// it is re-read on every sync, so workers can join and leave
+	WorkerDiscoveryFile string
+
+	// SnapshotFile, when set, keeps a copy of the last-known-good config
+	// outside of the cache
+	SnapshotFile string
+
+	// ControllerGRPC, when set, is used to talk to the controller instead of
+	// its REST API at ControllerURL
+	ControllerGRPC controllerpb.ControllerServiceClient
+}
+
+type registerRequest struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels"`
 }
 
 type configResponse struct {
-	AgentID      string `json:"agent_id"`
-	PollURL      string `json:"poll_url"`
-	PollInterval int    `json:"poll_interval"`
-	Version      int    `json:"version"`
+	AgentID       string `json:"agent_id"`
+	Namespace     string `json:"namespace"`
+	PollURL       string `json:"poll_url"`
+	PollInterval  int    `json:"poll_interval"`
+	Version       int    `json:"version"`
+	GlobalVersion int    `json:"global_version"` // namespace config version, without the agent override
+
+	// Config is the full config document, forwarded to the worker as-is.
+	Config json.RawMessage `json:"config,omitempty"`
+	// Signature is the controller's signature of Version, Namespace, the
+	// agent ID and Config, forwarded to the worker unchanged.
+	Signature string `json:"signature,omitempty"`
+}
+
+// served reports whether the config was served by the controller. Versions
+// start at 1, the registration response carries none.
+func (c configResponse) served() bool {
+	return c.Version > 0
 }
 
 type workerConfig struct {
 	URL string `json:"url"`
 }
 
-func NewAgentService(controllerURL, workerURL, apiKey string, cache repository.ICache) IAgentService {
-	tlsCfg := &tls.Config{InsecureSkipVerify: true} // self-signed certs on internal network
+// workerPayload returns the config pushed to the worker: the whole config
+// document, fields unknown to the agent included, or only the target URL
+// when the controller does not serve documents.
+func (c configResponse) workerPayload() ([]byte, error) {
+	if len(c.Config) > 0 {
+		return c.Config, nil
+	}
+	return json.Marshal(workerConfig{URL: c.PollURL})
+}
+
+func NewAgentService(config AgentConfig, cache repository.ICache) IAgentService {
 	httpClient := &http.Client{
-		Transport: &http.Transport{TLSClientConfig: tlsCfg},
+		Transport: config.Transport,
 		Timeout:   30 * time.Second,
 	}
+
+	var controller controllerAPI = &restController{
+		baseURL:      config.ControllerURL,
+		apiKey:       config.APIKey,
+		httpClient:   httpClient,
+		streamClient: &http.Client{Transport: config.Transport},
+	}
+	if config.ControllerGRPC != nil {
+		controller = &grpcController{client: config.ControllerGRPC}
+	}
+
 	return &AgentService{
-		controllerURL: controllerURL,
-		workerURL:     workerURL,
-		apiKey:        apiKey,
-		cache:         cache,
-		httpClient:    httpClient,
+		controller:   controller,
+		agentName:    config.AgentName,
+		namespace:    config.Namespace,
+		labels:       config.Labels,
+		workers:      workerPool{static: config.WorkerURLs, discoveryFile: config.WorkerDiscoveryFile},
+		workerAPIKey: config.WorkerAPIKey,
+		syncMode:     config.SyncMode,
+		cache:        cache,
+		httpClient:   httpClient,
+		buildInfo:    readBuildInfo(),
+		snapshotFile: config.SnapshotFile,
 	}
 }
 
 //go:generate mockgen -destination=mocks/agent.go -source=agent.go IAgentService
 type IAgentService interface {
 	RegisterAgent(ctx context.Context) error
+	Status() AgentStatus
 }
 
+// RegisterAgent registers the agent and keeps its workers in sync until ctx
+// is done, then deregisters it from the controller. While the controller is
+// unreachable, the workers get the last-known-good config and registration
+// is retried in the background of it.
 func (p *AgentService) RegisterAgent(ctx context.Context) error {
-	agentName := fmt.Sprintf("agent-%s", randomString(6))
-	body, err := json.Marshal(map[string]string{"name": agentName})
+	identity, err := p.loadIdentity(ctx)
 	if err != nil {
-		slog.Error("RegisterAgent failed to marshal registration data:", slog.Any("error", err))
+		slog.Error("RegisterAgent failed to load agent identity", slog.Any("error", err))
 		return err
 	}
 
-	req, err := http.NewRequest("POST", p.controllerURL+"/register", bytes.NewBuffer(body))
-	if err != nil {
-		slog.Error("RegisterAgent failed to create registration request:", slog.Any("error", err))
-		return err
+	if p.agentName != "" {
+		identity.Name = p.agentName
+	}
+	if identity.Name == "" {
+		identity.Name = fmt.Sprintf("agent-%s", randomString(6))
 	}
 
-	req.Header.Set("X-API-Key", p.apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := p.httpClient.Do(req)
+	regResp, err := p.register(ctx, identity)
 	if err != nil {
-		slog.Error("RegisterAgent failed to do registration request:", slog.Any("error", err))
-		return err
+		slog.Error("RegisterAgent failed to register agent, starting in degraded mode", slog.Any("error", err))
+
+		if err := p.bootOffline(ctx, &identity); err != nil {
+			slog.Warn("RegisterAgent failed to serve last-known-good config", slog.Any("error", err))
+		}
+
+		regResp, err = p.retryRegister(ctx, identity)
+		if err != nil {
+			// ctx is done before the controller came back
+			return nil
+		}
 	}
-	defer resp.Body.Close()
 
-	var regResp configResponse
-	if err := json.NewDecoder(resp.Body).Decode(&regResp); err != nil {
-		slog.Error("RegisterAgent failed to decode registration response:", slog.Any("error", err))
+	identity.AgentID = regResp.AgentID
+	if err := p.saveIdentity(ctx, identity); err != nil {
+		slog.Error("RegisterAgent failed to save agent identity", slog.Any("error", err))
 		return err
 	}
 
-	p.agentID = regResp.AgentID
+	p.setAgentID(regResp.AgentID)
+	p.state.update(func(status *AgentStatus) { status.Registered = true })
 	p.poolingInterval = regResp.PollInterval
 	if p.poolingInterval == 0 {
-		p.poolingInterval = 5 // default pooling interval
+		p.poolingInterval = defaultPoolingInterval
 	}
 
-	regRespJSON, err := json.Marshal(regResp)
-	if err != nil {
-		slog.Error("RegisterAgent failed to marshal config response", slog.Any("error", err))
-		return err
-	}
+	// the registration response carries no config, so the cache keeps the
+	// last one the controller served until the poller fetches a newer one
+
+	slog.Info("Registered with controller, starting poller", slog.String("agent_id", identity.AgentID), slog.String("name", identity.Name), slog.String("namespace", regResp.Namespace))
 
-	if err := p.cache.SetKey(ctx, fmt.Sprintf("config_agent:%s", regResp.AgentID), string(regRespJSON)); err != nil {
-		slog.Error("RegisterAgent failed to set key", slog.Any("error", err))
+	p.pooling(ctx)
+
+	// ctx is done by now, deregistering gets a deadline of its own
+	deregisterCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := p.controller.deregister(deregisterCtx, p.agentID); err != nil {
+		slog.Error("RegisterAgent failed to deregister agent", slog.Any("error", err))
 		return err
 	}
 
-	slog.Info("Registered with controller, starting poller")
-
-	p.pooling(ctx)
+	slog.Info("Deregistered from controller", slog.String("agent_id", p.agentID))
 
 	return nil
 }
 
+func (p *AgentService) setAgentID(agentID string) {
+	p.agentID = agentID
+	p.state.update(func(status *AgentStatus) { status.AgentID = agentID })
+}
+
 func randomString(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	b := make([]byte, length)
@@ -119,15 +237,47 @@ func randomString(length int) string {
 	return string(b)
 }
 
+// pooling syncs the config with the controller until ctx is done.
 func (p *AgentService) pooling(ctx context.Context) {
 	backoff := time.Second
 
 	// start polling with backoff
-	for {
+	for ctx.Err() == nil {
+		if err := p.sendHeartbeat(ctx); err != nil {
+			slog.Warn("pooling failed to send heartbeat", slog.Any("error", err))
+		}
+		if err := p.reconcileWorkers(ctx); err != nil {
+			slog.Warn("pooling failed to reconcile workers", slog.Any("error", err))
+		}
+
+		if p.syncMode == SyncModeWatch {
+			err := p.configWatch(ctx)
+			if err == nil {
+				backoff = time.Second
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Warn("pooling failed to watch config, falling back to interval polling", slog.Any("error", err))
+		}
+
+		if p.syncMode == SyncModeStream {
+			// the stream only ends when it breaks, reopened after one poll
+			err := p.configStream(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Warn("pooling config stream ended, falling back to interval polling", slog.Any("error", err))
+		}
+
 		err := p.configCheck(ctx)
 		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
 			slog.Error("pooling failed to check config", slog.Any("error", err))
-			time.Sleep(backoff)
+			sleep(ctx, backoff)
 			if backoff < 30*time.Second {
 				backoff *= 2
 			}
@@ -135,127 +285,176 @@ func (p *AgentService) pooling(ctx context.Context) {
 		}
 
 		backoff = time.Second
-		time.Sleep(time.Duration(p.poolingInterval) * time.Second)
+		sleep(ctx, time.Duration(p.poolingInterval)*time.Second)
+	}
+}
+
+// sleep waits for d, or less when ctx is done.
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
 	}
 }
 
 func (p *AgentService) configCheck(ctx context.Context) error {
-	var cachedConfig configResponse
-	cachedConfigString, err := p.cache.GetKey(ctx, fmt.Sprintf("config_agent:%s", p.agentID))
+	cachedConfig, err := p.getCachedConfig(ctx)
 	if err != nil {
-		slog.Error("configCheck failed to get old config from cache", slog.Any("error", err))
+		slog.Error("configCheck failed to get cached config", slog.Any("error", err))
 		return err
 	}
 
-	if err := json.Unmarshal([]byte(cachedConfigString), &cachedConfig); err != nil {
-		slog.Error("configCheck failed to unmarshal old config", slog.Any("error", err))
+	newConfig, err := p.controller.getConfig(ctx, p.agentID)
+	if err != nil {
+		slog.Error("configCheck failed to get config", slog.Any("error", err))
+		p.state.controllerFailed(err)
 		return err
 	}
+	p.state.controllerReached()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.controllerURL+"/config", nil)
+	return p.syncConfig(ctx, newConfig, cachedConfig)
+}
+
+// configWatch long-polls the controller until the config version differs from
+// the cached one. It returns nil without changes when the watch times out.
+func (p *AgentService) configWatch(ctx context.Context) error {
+	cachedConfig, err := p.getCachedConfig(ctx)
 	if err != nil {
-		slog.Error("configCheck failed to create request", slog.Any("error", err))
+		slog.Error("configWatch failed to get cached config", slog.Any("error", err))
 		return err
 	}
 
-	req.Header.Set("X-API-Key", p.apiKey)
-
-	resp, err := p.httpClient.Do(req)
+	newConfig, err := p.controller.watchConfig(ctx, p.agentID, cachedConfig.Version)
+	if errors.Is(err, errNotModified) {
+		slog.Info("configWatch config is up to date", slog.Any("version", cachedConfig.Version))
+		p.state.controllerReached()
+		return nil
+	}
 	if err != nil {
-		slog.Error("configCheck failed to do request", slog.Any("error", err))
+		slog.Error("configWatch failed to watch config", slog.Any("error", err))
+		p.state.controllerFailed(err)
 		return err
 	}
-	defer resp.Body.Close()
+	p.state.controllerReached()
+
+	return p.syncConfig(ctx, newConfig, cachedConfig)
+}
 
-	var newConfig configResponse
-	if err := json.NewDecoder(resp.Body).Decode(&newConfig); err != nil {
-		slog.Error("configCheck failed to decode config", slog.Any("error", err))
+// configStream applies every config the controller streams, resuming from
+// the cached version. Heartbeats are sent on a ticker while it runs.
+func (p *AgentService) configStream(ctx context.Context) error {
+	cachedConfig, err := p.getCachedConfig(ctx)
+	if err != nil {
+		slog.Error("configStream failed to get cached config", slog.Any("error", err))
 		return err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		slog.Error("configCheck failed to get config", slog.Any("status", resp.StatusCode))
-		return errors.New("configCheck failed to get config")
+	heartbeatInterval := time.Duration(p.poolingInterval) * time.Second
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = 5 * time.Second // default pooling interval
 	}
 
-	versionString := resp.Header.Get("Version")
-	if versionString == "" {
-		slog.Error("configCheck failed to get version from header")
-		return errors.New("version not found in header")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go p.heartbeatLoop(ctx, heartbeatInterval)
+
+	return p.controller.streamConfig(ctx, p.agentID, cachedConfig.Version, func(newConfig configResponse) error {
+		if err := p.syncConfig(ctx, newConfig, cachedConfig); err != nil {
+			return err
+		}
+		cachedConfig = newConfig
+		return nil
+	})
+}
+
+// getCachedConfig returns the last config the controller served and all
+// workers applied, or an empty one before there was any.
+func (p *AgentService) getCachedConfig(ctx context.Context) (configResponse, error) {
+	cachedConfig, err := p.cachedConfigOf(ctx, p.agentID)
+	if errors.Is(err, repository.ErrKeyNotFound) {
+		return configResponse{}, nil
 	}
+	if err != nil {
+		slog.Error("getCachedConfig failed to get old config from cache", slog.Any("error", err))
+		return cachedConfig, err
+	}
+
+	return cachedConfig, nil
+}
 
-	newVersion, err := strconv.Atoi(versionString)
+// syncConfig pushes the config fetched from the controller to every worker
+// that has not applied its version yet, workers that joined late or failed
+// before included. The config is only cached once all workers applied it.
+func (p *AgentService) syncConfig(ctx context.Context, newConfig, cachedConfig configResponse) error {
+	p.syncMu.Lock()
+	defer p.syncMu.Unlock()
+
+	return p.syncConfigLocked(ctx, newConfig, cachedConfig)
+}
+
+func (p *AgentService) syncConfigLocked(ctx context.Context, newConfig, cachedConfig configResponse) error {
+	newVersion := newConfig.Version
+
+	workerURLs, err := p.workers.urls()
 	if err != nil {
-		slog.Error("configCheck failed to convert version to int", slog.Any("error", err))
+		slog.Error("syncConfig failed to list workers", slog.Any("error", err))
 		return err
 	}
+	if len(workerURLs) == 0 {
+		return errNoWorkers
+	}
 
 	// check version
-	if newVersion == cachedConfig.Version {
-		slog.Info("configCheck config is up to date", slog.Any("version", newVersion))
+	pending := p.pendingWorkers(ctx, workerURLs, newVersion)
+	if len(pending) == 0 && newVersion == cachedConfig.Version {
+		slog.Info("syncConfig config is up to date", slog.Any("version", newVersion))
+		p.state.configApplied(ConfigSourceController, newVersion)
+		p.saveSnapshot(newConfig)
 		return nil
 	}
 
-	slog.Info("configCheck config is out of date, sending new config", slog.Any("version", newVersion))
-
 	// update cached config
 	newConfig.AgentID = p.agentID
-	newConfig.Version = newVersion
 
 	cfgJSON, err := json.Marshal(newConfig)
 	if err != nil {
-		slog.Error("configCheck failed to marshal config", slog.Any("error", err))
+		slog.Error("syncConfig failed to marshal config", slog.Any("error", err))
 		return err
 	}
 
-	// send config to worker
-	if err := p.sendConfig(workerConfig{
-		URL: newConfig.PollURL,
-	}); err != nil {
-		slog.Error("configCheck failed to send config", slog.Any("error", err))
-		return err
+	if len(pending) > 0 {
+		slog.Info("syncConfig workers are out of date, sending new config", slog.Any("version", newVersion), slog.Int("workers", len(pending)), slog.Int("total", len(workerURLs)))
+
+		workerPayload, err := newConfig.workerPayload()
+		if err != nil {
+			slog.Error("syncConfig failed to marshal worker config", slog.Any("error", err))
+			return err
+		}
+
+		// send config to workers
+		if err := p.pushConfig(ctx, pending, workerPayload, newConfig); err != nil {
+			slog.Error("syncConfig failed to send config", slog.Any("error", err))
+			return err
+		}
 	}
 
 	// update cached config
 	if err := p.cache.SetKey(ctx, fmt.Sprintf("config_agent:%s", p.agentID), string(cfgJSON)); err != nil {
-		slog.Error("configCheck failed to set key", slog.Any("error", err))
+		slog.Error("syncConfig failed to set key", slog.Any("error", err))
 		return err
 	}
 
+	p.state.configApplied(ConfigSourceController, newVersion)
+	p.saveSnapshot(newConfig)
+
 	// update pooling interval
 	p.poolingInterval = newConfig.PollInterval
-
-	return nil
-}
-
-func (c *AgentService) sendConfig(cfg workerConfig) error {
-	body, err := json.Marshal(cfg)
-	if err != nil {
-		slog.Error("sendConfig Failed to marshal config", slog.Any("error", err))
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, c.workerURL+"/config", bytes.NewBuffer(body))
-	if err != nil {
-		slog.Error("sendConfig Failed to create request", slog.Any("error", err))
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		slog.Error("sendConfig Failed to send config", slog.Any("error", err))
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		slog.Error("sendConfig failed to send config", slog.Any("status", resp.StatusCode))
-		return errors.New("sendConfig failed to send config")
+	if p.poolingInterval == 0 {
+		p.poolingInterval = defaultPoolingInterval
 	}
 
-	slog.Info("sendConfig update config to worker success")
 	return nil
 }
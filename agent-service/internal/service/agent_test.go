@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// fakeController registers the agent but cannot serve its config, as when
+// the controller fails right after a restart of the agent.
+type fakeController struct {
+	cancel         context.CancelFunc
+	appliedVersion int
+}
+
+func (c *fakeController) register(ctx context.Context, agentID string, req registerRequest) (configResponse, error) {
+	return configResponse{AgentID: agentID, Namespace: req.Namespace, PollInterval: 5}, nil
+}
+
+func (c *fakeController) getConfig(ctx context.Context, agentID string) (configResponse, error) {
+	return configResponse{}, errors.New("controller unavailable")
+}
+
+func (c *fakeController) watchConfig(ctx context.Context, agentID string, sinceVersion int) (configResponse, error) {
+	return configResponse{}, errors.New("controller unavailable")
+}
+
+func (c *fakeController) streamConfig(ctx context.Context, agentID string, sinceVersion int, apply func(configResponse) error) error {
+	return errors.New("controller unavailable")
+}
+
+// heartbeat records the reported version and stops the agent.
+func (c *fakeController) heartbeat(ctx context.Context, agentID string, req heartbeatRequest) error {
+	c.appliedVersion = req.AppliedVersion
+	c.cancel()
+	return nil
+}
+
+func (c *fakeController) deregister(ctx context.Context, agentID string) error {
+	return nil
+}
+
+func TestRegisterAgentKeepsServedConfig(t *testing.T) {
+	worker := &fakeWorker{}
+	p := newTestAgent(t, worker)
+
+	served := configResponse{
+		AgentID:       "agent-1",
+		Namespace:     "default",
+		PollInterval:  5,
+		Version:       3,
+		GlobalVersion: 2,
+		Config:        json.RawMessage(`{"url":"https://example.com","poll_interval":5}`),
+	}
+	cacheConfig(t, p, served)
+	if err := p.saveIdentity(context.Background(), agentIdentity{AgentID: "agent-1", Name: "agent-1"}); err != nil {
+		t.Fatalf("saveIdentity() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	controller := &fakeController{cancel: cancel}
+	p.controller = controller
+
+	if err := p.RegisterAgent(ctx); err != nil {
+		t.Fatalf("RegisterAgent() error = %v", err)
+	}
+
+	cached, err := p.getCachedConfig(context.Background())
+	if err != nil {
+		t.Fatalf("getCachedConfig() error = %v", err)
+	}
+	if cached.Version != served.Version || string(cached.Config) != string(served.Config) {
+		t.Errorf("cached config = version %d %s, want version %d %s", cached.Version, cached.Config, served.Version, served.Config)
+	}
+	if controller.appliedVersion != served.GlobalVersion {
+		t.Errorf("heartbeat reported version %d, want %d", controller.appliedVersion, served.GlobalVersion)
+	}
+}
+
+func TestGetCachedConfigBeforeFirstConfig(t *testing.T) {
+	p := newTestAgent(t, &fakeWorker{})
+
+	cached, err := p.getCachedConfig(context.Background())
+	if err != nil {
+		t.Fatalf("getCachedConfig() error = %v", err)
+	}
+	if cached.served() {
+		t.Errorf("getCachedConfig() = version %d, want no served config", cached.Version)
+	}
+}
+
+func TestSyncConfigWithoutPollInterval(t *testing.T) {
+	p := newTestAgent(t, &fakeWorker{})
+	p.poolingInterval = 30
+
+	served := configResponse{
+		AgentID: "agent-1",
+		Version: 1,
+		Config:  json.RawMessage(`{"url":"https://example.com"}`),
+	}
+	if err := p.syncConfig(context.Background(), served, configResponse{}); err != nil {
+		t.Fatalf("syncConfig() error = %v", err)
+	}
+
+	if p.poolingInterval != defaultPoolingInterval {
+		t.Errorf("poolingInterval = %d, want %d", p.poolingInterval, defaultPoolingInterval)
+	}
+}
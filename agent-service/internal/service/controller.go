@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+// errNotModified is returned by controllerAPI.watchConfig when the config
+// version did not change before the watch timed out.
+var errNotModified = errors.New("config not modified")
+
+// controllerAPI is the protocol the agent talks to the controller with, its
+// REST API (restController) or its gRPC API (grpcController).
+type controllerAPI interface {
+	// register creates the agent, or updates it when agentID is set, and
+	// returns its initial config.
+	register(ctx context.Context, agentID string, req registerRequest) (configResponse, error)
+	// getConfig returns the config served to the agent, with Version set.
+	getConfig(ctx context.Context, agentID string) (configResponse, error)
+	// watchConfig waits for a config whose version differs from
+	// sinceVersion, returning errNotModified when none arrives in time.
+	watchConfig(ctx context.Context, agentID string, sinceVersion int) (configResponse, error)
+	// streamConfig calls apply with every config whose version differs from
+	// the previous one, starting from sinceVersion, until the stream breaks
+	// or apply fails.
+	streamConfig(ctx context.Context, agentID string, sinceVersion int, apply func(configResponse) error) error
+	heartbeat(ctx context.Context, agentID string, req heartbeatRequest) error
+	// deregister marks the agent offline while it shuts down.
+	deregister(ctx context.Context, agentID string) error
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"agent-service/internal/controllerpb"
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcWatchTimeout bounds a WatchConfig stream, so the poller still sends
+// heartbeats while the config does not change, as with the REST watch.
+const grpcWatchTimeout = 25 * time.Second
+
+// grpcController talks to the controller's gRPC API.
+type grpcController struct {
+	client controllerpb.ControllerServiceClient
+}
+
+func (c *grpcController) register(ctx context.Context, agentID string, payload registerRequest) (configResponse, error) {
+	resp, err := c.client.Register(ctx, &controllerpb.RegisterRequest{
+		AgentId:   agentID,
+		Name:      payload.Name,
+		Namespace: payload.Namespace,
+		Labels:    payload.Labels,
+	})
+	if err != nil {
+		return configResponse{}, err
+	}
+	return fromConfigResponse(resp), nil
+}
+
+func (c *grpcController) getConfig(ctx context.Context, agentID string) (configResponse, error) {
+	resp, err := c.client.GetConfig(ctx, &controllerpb.GetConfigRequest{AgentId: agentID})
+	if err != nil {
+		return configResponse{}, err
+	}
+	return fromConfigResponse(resp), nil
+}
+
+// watchConfig opens a WatchConfig stream and returns its first config, which
+// the controller only sends once the version differs from sinceVersion.
+func (c *grpcController) watchConfig(ctx context.Context, agentID string, sinceVersion int) (configResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, grpcWatchTimeout)
+	defer cancel()
+
+	stream, err := c.client.WatchConfig(ctx, &controllerpb.WatchConfigRequest{
+		AgentId:      agentID,
+		SinceVersion: int64(sinceVersion),
+	})
+	if err != nil {
+		return configResponse{}, err
+	}
+
+	resp, err := stream.Recv()
+	if status.Code(err) == codes.DeadlineExceeded && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return configResponse{}, errNotModified
+	}
+	if err != nil {
+		return configResponse{}, err
+	}
+	return fromConfigResponse(resp), nil
+}
+
+// streamConfig keeps a WatchConfig stream open for as long as it delivers.
+func (c *grpcController) streamConfig(ctx context.Context, agentID string, sinceVersion int, apply func(configResponse) error) error {
+	stream, err := c.client.WatchConfig(ctx, &controllerpb.WatchConfigRequest{
+		AgentId:      agentID,
+		SinceVersion: int64(sinceVersion),
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if err := apply(fromConfigResponse(resp)); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *grpcController) heartbeat(ctx context.Context, agentID string, payload heartbeatRequest) error {
+	_, err := c.client.Heartbeat(ctx, &controllerpb.HeartbeatRequest{
+		AgentId:        agentID,
+		AppliedVersion: int64(payload.AppliedVersion),
+		WorkerHealthy:  payload.WorkerHealthy,
+		BuildInfo:      payload.BuildInfo,
+	})
+	return err
+}
+
+func (c *grpcController) deregister(ctx context.Context, agentID string) error {
+	_, err := c.client.Deregister(ctx, &controllerpb.DeregisterRequest{AgentId: agentID})
+	return err
+}
+
+func fromConfigResponse(resp *controllerpb.ConfigResponse) configResponse {
+	return configResponse{
+		AgentID:       resp.GetAgentId(),
+		Namespace:     resp.GetNamespace(),
+		PollURL:       resp.GetPollUrl(),
+		PollInterval:  int(resp.GetPollInterval()),
+		Version:       int(resp.GetVersion()),
+		GlobalVersion: int(resp.GetGlobalVersion()),
+		Config:        resp.GetConfig(),
+		Signature:     resp.GetSignature(),
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// streamIdleTimeout drops a config stream that went silent; the
+	// controller writes keep-alives well within it.
+	streamIdleTimeout = 60 * time.Second
+	// maxStreamLine bounds a single line of the config stream, and so the
+	// size of a config document.
+	maxStreamLine = 4 << 20
+)
+
+// restController talks to the controller's REST API.
+type restController struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	// streamClient has no timeout, GET /config/stream stays open
+	streamClient *http.Client
+}
+
+func (c *restController) register(ctx context.Context, agentID string, payload registerRequest) (configResponse, error) {
+	var regResp configResponse
+
+	method, path := http.MethodPost, "/register"
+	if agentID != "" {
+		method, path = http.MethodPut, "/agents/"+agentID
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("register failed to marshal registration data:", slog.Any("error", err))
+		return regResp, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewBuffer(body))
+	if err != nil {
+		slog.Error("register failed to create registration request:", slog.Any("error", err))
+		return regResp, err
+	}
+
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		slog.Error("register failed to do registration request:", slog.Any("error", err))
+		return regResp, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		slog.Error("register failed to register agent", slog.Any("status", resp.StatusCode))
+		return regResp, errors.New("register failed to register agent")
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&regResp); err != nil {
+		slog.Error("register failed to decode registration response:", slog.Any("error", err))
+		return regResp, err
+	}
+
+	return regResp, nil
+}
+
+func (c *restController) getConfig(ctx context.Context, agentID string) (configResponse, error) {
+	return c.fetchConfig(ctx, agentID, "/config")
+}
+
+func (c *restController) watchConfig(ctx context.Context, agentID string, sinceVersion int) (configResponse, error) {
+	return c.fetchConfig(ctx, agentID, fmt.Sprintf("/config/watch?since_version=%d", sinceVersion))
+}
+
+// fetchConfig gets the config from the given controller path, taking its
+// version from the Version header.
+func (c *restController) fetchConfig(ctx context.Context, agentID, path string) (configResponse, error) {
+	var newConfig configResponse
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		slog.Error("fetchConfig failed to create request", slog.Any("error", err))
+		return newConfig, err
+	}
+
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("X-Agent-ID", agentID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		slog.Error("fetchConfig failed to do request", slog.Any("error", err))
+		return newConfig, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return newConfig, errNotModified
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		slog.Error("fetchConfig failed to get config", slog.Any("status", resp.StatusCode))
+		return newConfig, errors.New("fetchConfig failed to get config")
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&newConfig); err != nil {
+		slog.Error("fetchConfig failed to decode config", slog.Any("error", err))
+		return newConfig, err
+	}
+
+	versionString := resp.Header.Get("Version")
+	if versionString == "" {
+		slog.Error("fetchConfig failed to get version from header")
+		return newConfig, errors.New("version not found in header")
+	}
+
+	newConfig.Version, err = strconv.Atoi(versionString)
+	if err != nil {
+		slog.Error("fetchConfig failed to convert version to int", slog.Any("error", err))
+		return newConfig, err
+	}
+
+	return newConfig, nil
+}
+
+// streamConfig reads the server-sent events of GET /config/stream. Sending
+// sinceVersion as Last-Event-ID makes the controller skip a config the agent
+// already has, and resend one it failed to apply.
+func (c *restController) streamConfig(ctx context.Context, agentID string, sinceVersion int, apply func(configResponse) error) error {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, c.baseURL+"/config/stream", nil)
+	if err != nil {
+		slog.Error("streamConfig failed to create request", slog.Any("error", err))
+		return err
+	}
+
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("X-Agent-ID", agentID)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Last-Event-ID", strconv.Itoa(sinceVersion))
+
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		slog.Error("streamConfig failed to do request", slog.Any("error", err))
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		slog.Error("streamConfig failed to open stream", slog.Any("status", resp.StatusCode))
+		return errors.New("streamConfig failed to open stream")
+	}
+
+	idle := time.AfterFunc(streamIdleTimeout, cancel)
+	defer idle.Stop()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+
+	var id, event string
+	var data []string
+	for scanner.Scan() {
+		idle.Reset(streamIdleTimeout)
+
+		line := scanner.Text()
+		if line != "" {
+			// lines starting with a colon are keep-alive comments
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "id":
+				id = value
+			case "event":
+				event = value
+			case "data":
+				data = append(data, value)
+			}
+			continue
+		}
+
+		// a blank line ends the event
+		if event == "config" && len(data) > 0 {
+			var newConfig configResponse
+			if err := json.Unmarshal([]byte(strings.Join(data, "\n")), &newConfig); err != nil {
+				slog.Error("streamConfig failed to decode config", slog.Any("error", err))
+				return err
+			}
+
+			newConfig.Version, err = strconv.Atoi(id)
+			if err != nil {
+				slog.Error("streamConfig failed to convert event id to version", slog.Any("error", err))
+				return err
+			}
+
+			if err := apply(newConfig); err != nil {
+				return err
+			}
+		}
+		event, data = "", nil
+	}
+
+	if ctx.Err() == nil && streamCtx.Err() != nil {
+		return errors.New("streamConfig stream went idle")
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("streamConfig stream closed by controller")
+}
+
+func (c *restController) heartbeat(ctx context.Context, agentID string, payload heartbeatRequest) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("heartbeat failed to marshal heartbeat", slog.Any("error", err))
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/agents/%s/heartbeat", c.baseURL, agentID), bytes.NewBuffer(body))
+	if err != nil {
+		slog.Error("heartbeat failed to create request", slog.Any("error", err))
+		return err
+	}
+
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		slog.Error("heartbeat failed to do request", slog.Any("error", err))
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		slog.Error("heartbeat failed to send heartbeat", slog.Any("status", resp.StatusCode))
+		return errors.New("heartbeat failed to send heartbeat")
+	}
+
+	return nil
+}
+
+func (c *restController) deregister(ctx context.Context, agentID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/agents/%s/deregister", c.baseURL, agentID), nil)
+	if err != nil {
+		slog.Error("deregister failed to create request", slog.Any("error", err))
+		return err
+	}
+
+	req.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		slog.Error("deregister failed to do request", slog.Any("error", err))
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		slog.Error("deregister failed to deregister agent", slog.Any("status", resp.StatusCode))
+		return errors.New("deregister failed to deregister agent")
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+type heartbeatRequest struct {
+	AppliedVersion int               `json:"applied_version,omitempty"`
+	WorkerHealthy  bool              `json:"worker_healthy"`
+	BuildInfo      map[string]string `json:"build_info"`
+}
+
+// sendHeartbeat reports the applied config version, worker health and build
+// info of this agent to the controller.
+func (p *AgentService) sendHeartbeat(ctx context.Context) error {
+	// rollouts are tracked per namespace config version; before the
+	// controller served a config there is none to report
+	var appliedVersion int
+	if cachedConfig, err := p.getCachedConfig(ctx); err == nil {
+		appliedVersion = cachedConfig.GlobalVersion
+	}
+
+	return p.controller.heartbeat(ctx, p.agentID, heartbeatRequest{
+		AppliedVersion: appliedVersion,
+		WorkerHealthy:  p.checkWorkerHealth(ctx),
+		BuildInfo:      p.buildInfo,
+	})
+}
+
+// heartbeatLoop sends a heartbeat every interval until ctx is done.
+func (p *AgentService) heartbeatLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.sendHeartbeat(ctx); err != nil {
+				slog.Warn("heartbeatLoop failed to send heartbeat", slog.Any("error", err))
+			}
+			// the stream only delivers new versions, drifted workers are
+			// repaired here
+			if err := p.reconcileWorkers(ctx); err != nil {
+				slog.Warn("heartbeatLoop failed to reconcile workers", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// checkWorkerHealth reports whether every worker of the pool is healthy.
+func (p *AgentService) checkWorkerHealth(ctx context.Context) bool {
+	workerURLs, err := p.workers.urls()
+	if err != nil {
+		slog.Error("checkWorkerHealth failed to list workers", slog.Any("error", err))
+		return false
+	}
+	if len(workerURLs) == 0 {
+		return false
+	}
+
+	errs := fanOut(workerURLs, func(workerURL string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, workerURL+"/health", nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	})
+	for workerURL, err := range errs {
+		slog.Warn("checkWorkerHealth worker is unhealthy", slog.Any("error", err), slog.String("worker", workerURL))
+	}
+
+	return len(errs) == 0
+}
+
+func readBuildInfo() map[string]string {
+	info := map[string]string{
+		"go_version": runtime.Version(),
+	}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info["version"] = buildInfo.Main.Version
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info["revision"] = setting.Value
+		case "vcs.time":
+			info["revision_time"] = setting.Value
+		}
+	}
+
+	return info
+}
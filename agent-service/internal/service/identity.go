@@ -0,0 +1,46 @@
+package service
+
+import (
+	"agent-service/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// identityKey is the cache key holding the agent ID assigned by the
+// controller, so the same ID is reused across restarts.
+const identityKey = "agent_identity"
+
+type agentIdentity struct {
+	AgentID string `json:"agent_id"`
+	Name    string `json:"name"`
+}
+
+// loadIdentity returns the persisted identity, or an empty one when the agent
+// has never registered.
+func (p *AgentService) loadIdentity(ctx context.Context) (agentIdentity, error) {
+	var identity agentIdentity
+
+	identityString, err := p.cache.GetKey(ctx, identityKey)
+	if errors.Is(err, repository.ErrKeyNotFound) {
+		return identity, nil
+	}
+	if err != nil {
+		return identity, err
+	}
+
+	if err := json.Unmarshal([]byte(identityString), &identity); err != nil {
+		return identity, err
+	}
+
+	return identity, nil
+}
+
+func (p *AgentService) saveIdentity(ctx context.Context, identity agentIdentity) error {
+	identityJSON, err := json.Marshal(identity)
+	if err != nil {
+		return err
+	}
+
+	return p.cache.SetKey(ctx, identityKey, string(identityJSON))
+}
@@ -5,6 +5,7 @@
 package mock_service
 
 import (
+	service "agent-service/internal/service"
 	context "context"
 	reflect "reflect"
 
@@ -47,3 +48,17 @@ func (mr *MockIAgentServiceMockRecorder) RegisterAgent(ctx interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAgent", reflect.TypeOf((*MockIAgentService)(nil).RegisterAgent), ctx)
 }
+
+// Status mocks base method.
+func (m *MockIAgentService) Status() service.AgentStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].(service.AgentStatus)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockIAgentServiceMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockIAgentService)(nil).Status))
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"agent-service/internal/fileutil"
+	"agent-service/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// maxRegisterBackoff caps the delay between registration attempts while the
+// controller is unreachable.
+const maxRegisterBackoff = time.Minute
+
+// register registers the agent with the controller under its persisted ID,
+// recording whether the controller was reachable.
+func (p *AgentService) register(ctx context.Context, identity agentIdentity) (configResponse, error) {
+	// reuse the persisted ID so the controller keeps a single row per agent
+	regResp, err := p.controller.register(ctx, identity.AgentID, registerRequest{
+		Name:      identity.Name,
+		Namespace: p.namespace,
+		Labels:    p.labels,
+	})
+	if err != nil {
+		p.state.controllerFailed(err)
+		return regResp, err
+	}
+
+	p.state.controllerReached()
+	return regResp, nil
+}
+
+// retryRegister retries registering with a jittered exponential backoff until
+// it succeeds or ctx is done. Workers are kept in line with the
+// last-known-good config in between.
+func (p *AgentService) retryRegister(ctx context.Context, identity agentIdentity) (configResponse, error) {
+	backoff := time.Second
+	for {
+		// the jitter keeps a fleet of agents from retrying in lockstep once
+		// the controller comes back
+		sleep(ctx, backoff/2+time.Duration(rand.Int63n(int64(backoff/2)+1)))
+		if ctx.Err() != nil {
+			return configResponse{}, ctx.Err()
+		}
+
+		regResp, err := p.register(ctx, identity)
+		if err == nil {
+			return regResp, nil
+		}
+		slog.Warn("retryRegister failed to register agent", slog.Any("error", err), slog.Duration("backoff", backoff))
+
+		if p.agentID != "" {
+			if err := p.reconcileWorkers(ctx); err != nil {
+				slog.Warn("retryRegister failed to reconcile workers", slog.Any("error", err))
+			}
+		}
+
+		backoff = min(backoff*2, maxRegisterBackoff)
+	}
+}
+
+// bootOffline pushes the last-known-good config, from the cache or else the
+// snapshot file, to the workers while the controller is unreachable. It
+// adopts the snapshot's agent ID when the cache lost the identity.
+func (p *AgentService) bootOffline(ctx context.Context, identity *agentIdentity) error {
+	source := ConfigSourceCache
+
+	cachedConfig, err := p.cachedConfigOf(ctx, identity.AgentID)
+	// caches of earlier releases may hold the registration response instead
+	// of a served config
+	if errors.Is(err, repository.ErrKeyNotFound) || (err == nil && !cachedConfig.served()) {
+		source = ConfigSourceSnapshot
+		cachedConfig, err = p.loadSnapshot(identity.AgentID)
+		if err != nil {
+			return err
+		}
+		identity.AgentID = cachedConfig.AgentID
+
+		// the poller and drift repair read the config from the cache
+		cfgJSON, err := json.Marshal(cachedConfig)
+		if err != nil {
+			return err
+		}
+		if err := p.cache.SetKey(ctx, fmt.Sprintf("config_agent:%s", identity.AgentID), string(cfgJSON)); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	p.setAgentID(identity.AgentID)
+	p.state.configApplied(source, cachedConfig.Version)
+
+	slog.Info("Controller unreachable, serving last-known-good config", slog.String("agent_id", identity.AgentID), slog.String("source", source), slog.Any("version", cachedConfig.Version))
+
+	return p.reconcileWorkers(ctx)
+}
+
+// cachedConfigOf returns the cached config of the given agent, or
+// ErrKeyNotFound when there is none.
+func (p *AgentService) cachedConfigOf(ctx context.Context, agentID string) (configResponse, error) {
+	if agentID == "" {
+		return configResponse{}, repository.ErrKeyNotFound
+	}
+
+	var cachedConfig configResponse
+	cachedConfigString, err := p.cache.GetKey(ctx, fmt.Sprintf("config_agent:%s", agentID))
+	if err != nil {
+		return cachedConfig, err
+	}
+
+	if err := json.Unmarshal([]byte(cachedConfigString), &cachedConfig); err != nil {
+		return cachedConfig, err
+	}
+
+	return cachedConfig, nil
+}
+
+// loadSnapshot reads the snapshot file. A snapshot of another agent than
+// agentID, when set, is not used.
+func (p *AgentService) loadSnapshot(agentID string) (configResponse, error) {
+	var snapshot configResponse
+	if p.snapshotFile == "" {
+		return snapshot, errors.New("no cached config and no snapshot file configured")
+	}
+
+	data, err := os.ReadFile(p.snapshotFile)
+	if err != nil {
+		return snapshot, err
+	}
+
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, err
+	}
+
+	if snapshot.AgentID == "" || (agentID != "" && snapshot.AgentID != agentID) {
+		return snapshot, fmt.Errorf("snapshot file holds the config of agent %q", snapshot.AgentID)
+	}
+
+	return snapshot, nil
+}
+
+// saveSnapshot writes the config applied by all workers to the snapshot file
+// unless it already holds its version, replacing the file atomically so a
+// crash never leaves a partial snapshot.
+func (p *AgentService) saveSnapshot(config configResponse) {
+	if p.snapshotFile == "" || p.snapshotVersion == config.Version {
+		return
+	}
+
+	config.AgentID = p.agentID
+	cfgJSON, err := json.Marshal(config)
+	if err != nil {
+		slog.Warn("saveSnapshot failed to marshal config", slog.Any("error", err))
+		return
+	}
+
+	if err := fileutil.WriteAtomic(p.snapshotFile, cfgJSON); err != nil {
+		slog.Warn("saveSnapshot failed to write snapshot file", slog.Any("error", err), slog.String("file", p.snapshotFile))
+		return
+	}
+	p.snapshotVersion = config.Version
+}
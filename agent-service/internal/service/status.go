@@ -0,0 +1,93 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// ConfigSourceController is a config fetched from the controller.
+	ConfigSourceController = "controller"
+	// ConfigSourceCache is the last-known-good config of the Redis cache,
+	// served while the controller is unreachable at startup.
+	ConfigSourceCache = "cache"
+	// ConfigSourceSnapshot is the last-known-good config of the snapshot
+	// file, served when the cache holds none.
+	ConfigSourceSnapshot = "snapshot"
+)
+
+// AgentStatus is what the status port reports. The agent is degraded while
+// it cannot reach the controller; its workers keep the last config applied.
+type AgentStatus struct {
+	AgentID       string     `json:"agent_id"`
+	Registered    bool       `json:"registered"`
+	Degraded      bool       `json:"degraded"`
+	DegradedSince *time.Time `json:"degraded_since"`
+	LastError     string     `json:"last_error,omitempty"`
+	ConfigSource  string     `json:"config_source,omitempty"`
+	ConfigVersion int        `json:"config_version"`
+}
+
+// agentState holds the AgentStatus, updated by the poller and read by the
+// status port.
+type agentState struct {
+	mu     sync.Mutex
+	status AgentStatus
+}
+
+func (s *agentState) get() AgentStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *agentState) update(fn func(status *AgentStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.status)
+}
+
+// controllerFailed marks the agent as degraded after a failed controller call.
+func (s *agentState) controllerFailed(err error) {
+	s.update(func(status *AgentStatus) {
+		if !status.Degraded {
+			now := time.Now()
+			status.Degraded = true
+			status.DegradedSince = &now
+		}
+		status.LastError = err.Error()
+	})
+}
+
+// controllerReached clears the degraded mode after a successful controller
+// call.
+func (s *agentState) controllerReached() {
+	s.update(func(status *AgentStatus) {
+		status.Degraded = false
+		status.DegradedSince = nil
+		status.LastError = ""
+	})
+}
+
+func (s *agentState) configApplied(source string, version int) {
+	s.update(func(status *AgentStatus) {
+		status.ConfigSource = source
+		status.ConfigVersion = version
+	})
+}
+
+func (p *AgentService) Status() AgentStatus {
+	return p.state.get()
+}
+
+// NewStatusHandler serves the agent status as JSON on GET /status.
+func NewStatusHandler(agent IAgentService) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(agent.Status())
+	})
+	return mux
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"agent-service/internal/repository"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// errNoWorkers is returned by syncConfig while the pool has no workers, so
+// the config is not cached before any worker applied it.
+var errNoWorkers = errors.New("no workers configured")
+
+// workerPool lists the workers the agent keeps in sync: a static list, plus
+// the URLs of a discovery file read on every use, one per line.
+type workerPool struct {
+	static        []string
+	discoveryFile string
+}
+
+func (w workerPool) urls() ([]string, error) {
+	urls := slices.Clone(w.static)
+
+	if w.discoveryFile != "" {
+		file, err := os.Open(w.discoveryFile)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			urls = append(urls, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Sort(urls)
+	return slices.Compact(urls), nil
+}
+
+// fanOut calls fn for every worker concurrently and returns the errors by
+// worker URL.
+func fanOut(urls []string, fn func(workerURL string) error) map[string]error {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[string]error)
+	)
+
+	for _, workerURL := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(workerURL); err != nil {
+				mu.Lock()
+				errs[workerURL] = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return errs
+}
+
+func workerAppliedKey(agentID, workerURL string) string {
+	return fmt.Sprintf("worker_applied:%s:%s", agentID, workerURL)
+}
+
+// pendingWorkers returns the workers whose applied config version, as
+// recorded in the cache, is not version.
+func (p *AgentService) pendingWorkers(ctx context.Context, urls []string, version int) []string {
+	var pending []string
+	for _, workerURL := range urls {
+		applied, err := p.cache.GetKey(ctx, workerAppliedKey(p.agentID, workerURL))
+		if err != nil && !errors.Is(err, repository.ErrKeyNotFound) {
+			// pushing again is harmless, skipping a worker is not
+			slog.Warn("pendingWorkers failed to get applied version", slog.Any("error", err), slog.String("worker", workerURL))
+		}
+		if applied != strconv.Itoa(version) {
+			pending = append(pending, workerURL)
+		}
+	}
+	return pending
+}
+
+// workerState is the config a worker reports to apply.
+type workerState struct {
+	Version int             `json:"version"`
+	Config  json.RawMessage `json:"config"`
+}
+
+// reconcileWorkers compares the config every worker applies with the cached
+// one, independent of the controller version, and pushes it again to the
+// workers that drifted, e.g. after a restart lost their config, or that
+// joined since it was applied. Until the controller served a config there is
+// nothing to repair.
+func (p *AgentService) reconcileWorkers(ctx context.Context) error {
+	p.syncMu.Lock()
+	defer p.syncMu.Unlock()
+
+	cachedConfig, err := p.getCachedConfig(ctx)
+	if err != nil {
+		slog.Error("reconcileWorkers failed to get cached config", slog.Any("error", err))
+		return err
+	}
+	if !cachedConfig.served() {
+		return nil
+	}
+
+	workerURLs, err := p.workers.urls()
+	if err != nil {
+		slog.Error("reconcileWorkers failed to list workers", slog.Any("error", err))
+		return err
+	}
+
+	workerPayload, err := cachedConfig.workerPayload()
+	if err != nil {
+		slog.Error("reconcileWorkers failed to marshal worker config", slog.Any("error", err))
+		return err
+	}
+
+	var (
+		mu      sync.Mutex
+		drifted []string
+	)
+	fanOut(workerURLs, func(workerURL string) error {
+		state, err := p.getWorkerConfig(ctx, workerURL)
+		if err == nil && state.matches(cachedConfig.Version, workerPayload) {
+			return nil
+		}
+		if err != nil {
+			slog.Warn("reconcileWorkers failed to get worker config", slog.Any("error", err), slog.String("worker", workerURL))
+		}
+
+		mu.Lock()
+		drifted = append(drifted, workerURL)
+		mu.Unlock()
+		return nil
+	})
+	if len(drifted) == 0 {
+		return nil
+	}
+
+	slog.Info("reconcileWorkers workers drifted, sending config", slog.Any("version", cachedConfig.Version), slog.Int("workers", len(drifted)), slog.Int("total", len(workerURLs)))
+	return p.pushConfig(ctx, drifted, workerPayload, cachedConfig)
+}
+
+// matches reports whether the worker applies the given config version and
+// document.
+func (s workerState) matches(version int, payload []byte) bool {
+	if s.Version != version {
+		return false
+	}
+
+	var applied, desired bytes.Buffer
+	if err := json.Compact(&applied, s.Config); err != nil {
+		return false
+	}
+	if err := json.Compact(&desired, payload); err != nil {
+		return false
+	}
+	return bytes.Equal(applied.Bytes(), desired.Bytes())
+}
+
+// getWorkerConfig returns the config a worker applies.
+func (p *AgentService) getWorkerConfig(ctx context.Context, workerURL string) (workerState, error) {
+	var state workerState
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, workerURL+"/config", nil)
+	if err != nil {
+		return state, err
+	}
+	req.Header.Set("X-API-Key", p.workerAPIKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return state, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return state, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+// pushConfig sends a config to the given workers concurrently and records
+// the version each of them applied. A failing worker does not stop the
+// others; the returned error lists every failure.
+func (p *AgentService) pushConfig(ctx context.Context, urls []string, body []byte, config configResponse) error {
+	errs := fanOut(urls, func(workerURL string) error {
+		if err := p.sendConfig(ctx, workerURL, body, config); err != nil {
+			return err
+		}
+		return p.cache.SetKey(ctx, workerAppliedKey(p.agentID, workerURL), strconv.Itoa(config.Version))
+	})
+	if len(errs) == 0 {
+		return nil
+	}
+
+	failed := make([]error, 0, len(errs))
+	for workerURL, err := range errs {
+		failed = append(failed, fmt.Errorf("%s: %w", workerURL, err))
+	}
+	return fmt.Errorf("%d of %d workers failed to apply config version %d: %w", len(errs), len(urls), config.Version, errors.Join(failed...))
+}
+
+// sendConfig pushes a config to a worker. The version, namespace, agent ID
+// and signature let a worker holding the controller's public key verify the
+// config.
+func (c *AgentService) sendConfig(ctx context.Context, workerURL string, body []byte, config configResponse) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, workerURL+"/config", bytes.NewBuffer(body))
+	if err != nil {
+		slog.Error("sendConfig Failed to create request", slog.Any("error", err))
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", c.workerAPIKey)
+	req.Header.Set("X-Config-Version", strconv.Itoa(config.Version))
+	req.Header.Set("X-Config-Namespace", config.Namespace)
+	req.Header.Set("X-Config-Agent-ID", c.agentID)
+	if config.Signature != "" {
+		req.Header.Set("X-Config-Signature", config.Signature)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		slog.Error("sendConfig Failed to send config", slog.Any("error", err), slog.String("worker", workerURL))
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		slog.Error("sendConfig failed to send config", slog.Any("status", resp.StatusCode), slog.String("worker", workerURL))
+		return errors.New("sendConfig failed to send config")
+	}
+
+	slog.Info("sendConfig update config to worker success", slog.String("worker", workerURL))
+	return nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"agent-service/internal/repository/local"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// fakeWorker applies every config posted to it and reports it back, like
+// the worker service without a verify key.
+type fakeWorker struct {
+	mu      sync.Mutex
+	version int
+	config  json.RawMessage
+	pushes  int
+}
+
+func (w *fakeWorker) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if r.Method == http.MethodGet {
+		json.NewEncoder(rw).Encode(workerState{Version: w.version, Config: w.config})
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	w.version, _ = strconv.Atoi(r.Header.Get("X-Config-Version"))
+	w.config = body
+	w.pushes++
+}
+
+func (w *fakeWorker) pushCount() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.pushes
+}
+
+func newTestAgent(t *testing.T, worker http.Handler) *AgentService {
+	t.Helper()
+
+	server := httptest.NewServer(worker)
+	t.Cleanup(server.Close)
+
+	return &AgentService{
+		agentID:    "agent-1",
+		workers:    workerPool{static: []string{server.URL}},
+		cache:      local.NewMemoryCache(),
+		httpClient: server.Client(),
+	}
+}
+
+func cacheConfig(t *testing.T, p *AgentService, config configResponse) {
+	t.Helper()
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if err := p.cache.SetKey(context.Background(), fmt.Sprintf("config_agent:%s", p.agentID), string(data)); err != nil {
+		t.Fatalf("SetKey() error = %v", err)
+	}
+}
+
+func TestReconcileWorkers(t *testing.T) {
+	tests := []struct {
+		name       string
+		cached     configResponse
+		wantPushes int
+	}{
+		{
+			// what registration used to cache: no version, no document
+			name:       "registration payload",
+			cached:     configResponse{AgentID: "agent-1", Namespace: "default", PollInterval: 5},
+			wantPushes: 0,
+		},
+		{
+			name: "served config",
+			cached: configResponse{
+				AgentID:       "agent-1",
+				Namespace:     "default",
+				PollInterval:  5,
+				Version:       3,
+				GlobalVersion: 2,
+				Config:        json.RawMessage(`{"url":"https://example.com","poll_interval":5}`),
+			},
+			wantPushes: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worker := &fakeWorker{}
+			p := newTestAgent(t, worker)
+			cacheConfig(t, p, tt.cached)
+
+			// the second run finds the worker in sync
+			for range 2 {
+				if err := p.reconcileWorkers(context.Background()); err != nil {
+					t.Fatalf("reconcileWorkers() error = %v", err)
+				}
+			}
+
+			if got := worker.pushCount(); got != tt.wantPushes {
+				t.Errorf("worker got %d pushes, want %d", got, tt.wantPushes)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Package tlsutil builds TLS configs whose certificates and CA bundles are
+// re-read from disk when they change, so rotated certificates are picked up
+// without a restart.
+package tlsutil
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Client holds the TLS settings used towards the controller and the worker.
+// Servers are verified against a CA bundle that is reloaded when it changes;
+// a client certificate, when configured, is reloaded the same way.
+type Client struct {
+	config  *tls.Config
+	rootCAs *reloader[*x509.CertPool]
+}
+
+// NewClient loads the CA bundle in caFile and the client key pair in
+// certFile and keyFile, both optional.
+//
+// Without caFile server certificates are not verified, which only suits
+// self-signed certificates on a trusted network.
+func NewClient(caFile, certFile, keyFile string) (*Client, error) {
+	c := &Client{config: &tls.Config{MinVersion: tls.VersionTLS12}}
+
+	if certFile != "" || keyFile != "" {
+		keyPair, err := newReloader(func() (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			return &cert, err
+		}, certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+
+		c.config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return keyPair.get(), nil
+		}
+	}
+
+	if caFile == "" {
+		slog.Warn("TLS_CA_FILE not set, server certificates are not verified")
+		c.config.InsecureSkipVerify = true
+		return c, nil
+	}
+
+	rootCAs, err := newReloader(func() (*x509.CertPool, error) {
+		return loadCertPool(caFile)
+	}, caFile)
+	if err != nil {
+		return nil, fmt.Errorf("load CA bundle: %w", err)
+	}
+	c.rootCAs = rootCAs
+
+	return c, nil
+}
+
+// Config returns the TLS config of a connection to serverName. RootCAs
+// cannot change on a live config, so every connection gets a copy holding
+// the current bundle.
+func (c *Client) Config(serverName string) *tls.Config {
+	config := c.config.Clone()
+	config.ServerName = serverName
+	if c.rootCAs != nil {
+		config.RootCAs = c.rootCAs.get()
+	}
+	return config
+}
+
+// Transport returns an HTTP transport dialing every connection with Config.
+func (c *Client) Transport() *http.Transport {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+	return &http.Transport{
+		DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+
+			tlsDialer := &tls.Dialer{NetDialer: dialer, Config: c.Config(host)}
+			return tlsDialer.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no certificates found in " + file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,33 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// CertificateIdentity names the holder of a certificate: its common name,
+// or its first DNS or URI subject alternative name when the CN is empty.
+func CertificateIdentity(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return ""
+}
+
+// KeyPairIdentity returns the CertificateIdentity of the leaf certificate in
+// a PEM key pair, the name a controller requiring client certificates
+// registers the agent under.
+func KeyPairIdentity(certFile, keyFile string) (string, error) {
+	keyPair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return "", fmt.Errorf("load client certificate: %w", err)
+	}
+	return CertificateIdentity(keyPair.Leaf), nil
+}
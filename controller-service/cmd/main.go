@@ -8,15 +8,27 @@
 package main
 
 import (
+	"context"
 	"controller-service/internal/api/handler"
 	"controller-service/internal/api/middleware"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/rpc"
 	"controller-service/internal/config"
 	"controller-service/internal/database"
+	"controller-service/internal/notifier"
 	queries "controller-service/internal/repository/sqlc"
 	"controller-service/internal/service"
+	"controller-service/internal/signing"
+	"controller-service/internal/tlsutil"
 	"database/sql"
+	"errors"
 	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	_ "controller-service/docs" // swagger docs
@@ -54,27 +66,203 @@ func main() {
 	}
 	slog.Info("Database migration successful")
 
+	configNotifier, err := notifier.NewConfigNotifier(cfg.DBURL)
+	if err != nil {
+		slog.Error("Failed to listen for config changes", slog.Any("error", err))
+		panic(err)
+	}
+
 	queries := queries.New(dbConn)
 
+	signer, err := signing.LoadSigner(cfg.ConfigSigningKeyFile, cfg.ConfigSigningKey)
+	if err != nil {
+		slog.Error("Failed to load config signing key", slog.Any("error", err))
+		panic(err)
+	}
+	if signer == nil {
+		slog.Warn("CONFIG_SIGNING_KEY_FILE and CONFIG_SIGNING_KEY not set, configs are served unsigned")
+	}
+
+	// ctx is done on SIGINT or SIGTERM; draining is closed then, ending open
+	// watches and streams so the servers can shut down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	draining := make(chan struct{})
+
 	svc := &service.ControllerService{
-		DB:   dbConn,
-		Repo: queries,
+		DB:       dbConn,
+		Repo:     queries,
+		Notifier: configNotifier,
+		Draining: draining,
+
+		AgentStaleAfter: time.Duration(cfg.AgentStaleSeconds) * time.Second,
+		Signer:          signer,
+
+		ApprovalNamespaces: cfg.ApprovalRequiredNamespaces,
+		ProposalTTL:        time.Duration(cfg.ProposalTTLHours) * time.Hour,
 	}
 
-	h := &handler.ControllerHandler{Service: svc}
+	// background loops stop with ctx and are waited for before the
+	// database is closed
+	var loops sync.WaitGroup
+	startLoop := func(run func(ctx context.Context)) {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			run(ctx)
+		}()
+	}
+
+	if cfg.AgentReapAfterDays > 0 {
+		startLoop(func(ctx context.Context) { svc.RunAgentReaper(ctx, time.Hour, cfg.AgentReapAfterDays) })
+	}
+
+	startLoop(func(ctx context.Context) { svc.RunWebhookDispatcher(ctx, 5*time.Second) })
+	startLoop(func(ctx context.Context) { svc.RunConfigScheduler(ctx, time.Second) })
+	startLoop(func(ctx context.Context) { svc.RunProposalExpirer(ctx, time.Minute) })
+
+	if cfg.ConfigSyncDir != "" {
+		startLoop(func(ctx context.Context) { svc.RunConfigSync(ctx, cfg.ConfigSyncDir, 5*time.Second) })
+	}
+
+	h := &handler.ControllerHandler{
+		Service:         svc,
+		WatchTimeout:    time.Duration(cfg.WatchTimeoutSeconds) * time.Second,
+		StreamKeepAlive: time.Duration(cfg.StreamKeepAliveSeconds) * time.Second,
+		Draining:        draining,
+	}
 
 	mux := http.NewServeMux()
-	auth := middleware.APIKeyAuth(cfg.APIKey)
+	auth := middleware.APIKeyAuth(cfg.APIKey, svc)
+
+	// agents hold agents:register and config:read, operators config:write
+	mux.Handle("POST /register", auth(request.ScopeAgentsRegister, http.HandlerFunc(h.Register)))
+	mux.Handle("GET /agents", auth(request.ScopeFleetRead, http.HandlerFunc(h.ListAgents)))
+	mux.Handle("PUT /agents/{id}", auth(request.ScopeAgentsRegister, http.HandlerFunc(h.ReregisterAgent)))
+	mux.Handle("POST /agents/{id}/heartbeat", auth(request.ScopeAgentsRegister, http.HandlerFunc(h.Heartbeat)))
+	mux.Handle("POST /agents/{id}/deregister", auth(request.ScopeAgentsRegister, http.HandlerFunc(h.DeregisterAgent)))
+	mux.Handle("GET /agents/{id}/config-override", auth(request.ScopeConfigRead, http.HandlerFunc(h.GetAgentConfigOverride)))
+	mux.Handle("PUT /agents/{id}/config-override", auth(request.ScopeConfigWrite, http.HandlerFunc(h.SetAgentConfigOverride)))
+	mux.Handle("DELETE /agents/{id}/config-override", auth(request.ScopeConfigWrite, http.HandlerFunc(h.ClearAgentConfigOverride)))
+	mux.Handle("GET /audit", auth(request.ScopeAdmin, http.HandlerFunc(h.ListAuditEvents)))
+	mux.Handle("GET /audit/export", auth(request.ScopeAdmin, http.HandlerFunc(h.ExportAuditEvents)))
+	mux.Handle("POST /api-keys", auth(request.ScopeAdmin, http.HandlerFunc(h.CreateAPIKey)))
+	mux.Handle("GET /api-keys", auth(request.ScopeAdmin, http.HandlerFunc(h.ListAPIKeys)))
+	mux.Handle("DELETE /api-keys/{id}", auth(request.ScopeAdmin, http.HandlerFunc(h.RevokeAPIKey)))
+	mux.Handle("POST /webhooks", auth(request.ScopeAdmin, http.HandlerFunc(h.CreateWebhook)))
+	mux.Handle("GET /webhooks", auth(request.ScopeAdmin, http.HandlerFunc(h.ListWebhooks)))
+	mux.Handle("GET /webhooks/{id}", auth(request.ScopeAdmin, http.HandlerFunc(h.GetWebhook)))
+	mux.Handle("PUT /webhooks/{id}", auth(request.ScopeAdmin, http.HandlerFunc(h.UpdateWebhook)))
+	mux.Handle("DELETE /webhooks/{id}", auth(request.ScopeAdmin, http.HandlerFunc(h.DeleteWebhook)))
+	mux.Handle("GET /webhooks/{id}/deliveries", auth(request.ScopeAdmin, http.HandlerFunc(h.ListWebhookDeliveries)))
 
-	mux.Handle("POST /register", auth(http.HandlerFunc(h.Register)))
-	mux.Handle("GET /config", auth(http.HandlerFunc(h.GetConfig)))
-	mux.Handle("POST /config", auth(http.HandlerFunc(h.UpdateConfig)))
+	// bundles span every namespace
+	mux.Handle("GET /config/export", auth(request.ScopeConfigRead, http.HandlerFunc(h.ExportConfig)))
+	mux.Handle("POST /config/import", auth(request.ScopeConfigWrite, http.HandlerFunc(h.ImportConfig)))
+
+	// config routes act on the default namespace (or the calling agent's
+	// namespace), and on {ns} under the /namespaces prefix
+	for _, prefix := range []string{"", "/namespaces/{ns}"} {
+		mux.Handle("GET "+prefix+"/config", auth(request.ScopeConfigRead, http.HandlerFunc(h.GetConfig)))
+		mux.Handle("POST "+prefix+"/config", auth(request.ScopeConfigWrite, http.HandlerFunc(h.UpdateConfig)))
+		mux.Handle("GET "+prefix+"/config/watch", auth(request.ScopeConfigRead, http.HandlerFunc(h.WatchConfig)))
+		mux.Handle("GET "+prefix+"/config/stream", auth(request.ScopeConfigRead, http.HandlerFunc(h.StreamConfig)))
+		mux.Handle("GET "+prefix+"/config/schema", auth(request.ScopeConfigRead, http.HandlerFunc(h.GetConfigSchema)))
+		mux.Handle("PUT "+prefix+"/config/schema", auth(request.ScopeConfigWrite, http.HandlerFunc(h.SetConfigSchema)))
+		mux.Handle("GET "+prefix+"/config/versions", auth(request.ScopeConfigRead, http.HandlerFunc(h.ListConfigVersions)))
+		mux.Handle("GET "+prefix+"/config/versions/{version}", auth(request.ScopeConfigRead, http.HandlerFunc(h.GetConfigVersion)))
+		mux.Handle("GET "+prefix+"/config/versions/{version}/rollout", auth(request.ScopeConfigRead, http.HandlerFunc(h.GetConfigRollout)))
+		mux.Handle("POST "+prefix+"/config/versions/{version}/promote", auth(request.ScopeConfigWrite, http.HandlerFunc(h.PromoteConfigVersion)))
+		mux.Handle("POST "+prefix+"/config/versions/{version}/abort", auth(request.ScopeConfigWrite, http.HandlerFunc(h.AbortConfigVersion)))
+		mux.Handle("GET "+prefix+"/config/diff", auth(request.ScopeConfigRead, http.HandlerFunc(h.DiffConfigVersions)))
+		mux.Handle("POST "+prefix+"/config/rollback/{version}", auth(request.ScopeConfigWrite, http.HandlerFunc(h.RollbackConfig)))
+		mux.Handle("GET "+prefix+"/config/scheduled", auth(request.ScopeConfigRead, http.HandlerFunc(h.ListScheduledConfigs)))
+		mux.Handle("DELETE "+prefix+"/config/scheduled/{id}", auth(request.ScopeConfigWrite, http.HandlerFunc(h.CancelScheduledConfig)))
+		mux.Handle("GET "+prefix+"/config/proposals", auth(request.ScopeConfigRead, http.HandlerFunc(h.ListConfigProposals)))
+		mux.Handle("POST "+prefix+"/config/proposals/{id}/approve", auth(request.ScopeConfigWrite, http.HandlerFunc(h.ApproveConfigProposal)))
+	}
 
 	mux.Handle("/docs/", httpSwagger.WrapHandler)
 
-	slog.Info("Starting HTTPS server at :" + cfg.AppPort)
-	if err := http.ListenAndServeTLS(":"+cfg.AppPort, cfg.TLSCertFile, cfg.TLSKeyFile, mux); err != nil {
-		slog.Error("ListenAndServeTLS: ", slog.Any("error", err))
+	tlsConfig, err := tlsutil.ServerConfig(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile, cfg.TLSClientAuth)
+	if err != nil {
+		slog.Error("Failed to configure TLS", slog.Any("error", err))
 		panic(err)
 	}
+
+	// the gRPC API shares the certificates but needs a config of its own
+	grpcTLSConfig, err := tlsutil.ServerConfig(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile, cfg.TLSClientAuth)
+	if err != nil {
+		slog.Error("Failed to configure gRPC TLS", slog.Any("error", err))
+		panic(err)
+	}
+
+	grpcServer := (&rpc.ControllerServer{
+		Service:      svc,
+		BootstrapKey: cfg.APIKey,
+		Keys:         svc,
+	}).NewGRPCServer(grpcTLSConfig)
+
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		slog.Error("Failed to listen for gRPC", slog.Any("error", err))
+		panic(err)
+	}
+
+	go func() {
+		slog.Info("Starting gRPC server at :" + cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			slog.Error("gRPC Serve: ", slog.Any("error", err))
+			panic(err)
+		}
+	}()
+
+	server := &http.Server{
+		Addr:      ":" + cfg.AppPort,
+		Handler:   middleware.RequestInfo(mux),
+		TLSConfig: tlsConfig,
+	}
+
+	go func() {
+		slog.Info("Starting HTTPS server at :" + cfg.AppPort)
+		// certificates come from tlsConfig, reloaded when they change on disk
+		if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("ListenAndServeTLS: ", slog.Any("error", err))
+			panic(err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // a second signal kills the process
+	slog.Info("Shutting down, draining in-flight requests", slog.Int("timeout_seconds", cfg.ShutdownTimeoutSeconds))
+	close(draining)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeoutSeconds)*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Failed to drain HTTPS server", slog.Any("error", err))
+	}
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		slog.Error("Failed to drain gRPC server, closing open streams")
+		grpcServer.Stop()
+	}
+
+	loops.Wait()
+
+	if err := configNotifier.Close(); err != nil {
+		slog.Error("Failed to close config notifier", slog.Any("error", err))
+	}
+	if err := dbConn.Close(); err != nil {
+		slog.Error("Failed to close database connection", slog.Any("error", err))
+	}
+	slog.Info("Shutdown complete")
 }
@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"controller-service/internal/api/request"
+	"controller-service/internal/service"
+	"controller-service/internal/tlsutil"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Reregister Agent godoc
+// @Summary Re-register agent
+// @Description Create or update an agent under a stable ID chosen by the agent. The name is bound to a verified client certificate like on POST /register. An existing agent can only be re-registered with the API key or client certificate it was registered with.
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Agent ID"
+// @Param body body request.RegisterAgentRequest true "Agent registration data"
+// @Success 200 {object} response.ConfigResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /agents/{id} [put]
+func (h *ControllerHandler) ReregisterAgent(w http.ResponseWriter, r *http.Request) {
+	agentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid agent id", http.StatusBadRequest)
+		return
+	}
+
+	var body request.RegisterAgentRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := agentNameFromCertificate(r, &body); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	agent, err := h.Service.ReregisterAgent(r.Context(), agentID, body)
+	if errors.Is(err, service.ErrAgentForbidden) {
+		http.Error(w, "Agent is registered by another identity", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to register agent", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(agent)
+}
+
+// Agent Heartbeat godoc
+// @Summary Agent heartbeat
+// @Description Report agent liveness, applied config version, worker health and build info, with the API key or client certificate the agent was registered with
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Agent ID"
+// @Param body body request.AgentHeartbeatRequest true "Heartbeat data"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /agents/{id}/heartbeat [post]
+func (h *ControllerHandler) Heartbeat(w http.ResponseWriter, r *http.Request) {
+	agentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid agent id", http.StatusBadRequest)
+		return
+	}
+
+	var body request.AgentHeartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.RecordHeartbeat(r.Context(), agentID, body)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Agent not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrAgentForbidden) {
+		http.Error(w, "Agent is registered by another identity", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to record heartbeat", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deregister Agent godoc
+// @Summary Deregister agent
+// @Description Mark an agent shutting down as offline. It is listed with status offline and left out of rollouts until it registers or sends a heartbeat again; its config override is kept. Requires the API key or client certificate the agent was registered with.
+// @Tags agents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Agent ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /agents/{id}/deregister [post]
+func (h *ControllerHandler) DeregisterAgent(w http.ResponseWriter, r *http.Request) {
+	agentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid agent id", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.DeregisterAgent(r.Context(), agentID)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Agent not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrAgentForbidden) {
+		http.Error(w, "Agent is registered by another identity", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to deregister agent", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List Agents godoc
+// @Summary List agents
+// @Description List registered agents with their liveness status. Requires the fleet:read scope.
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param namespace query string false "Only list agents of this namespace"
+// @Success 200 {object} response.AgentListResponse
+// @Failure 500 {object} map[string]interface{}
+// @Router /agents [get]
+func (h *ControllerHandler) ListAgents(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	if namespace != "" {
+		if err := request.ValidateNamespace(namespace); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	agents, err := h.Service.ListAgents(r.Context(), namespace)
+	if err != nil {
+		http.Error(w, "Failed to list agents", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(agents)
+}
+
+// Get Agent Config Override godoc
+// @Summary Get agent config override
+// @Description Get the config fields that replace the namespace config for this agent
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Agent ID"
+// @Success 200 {object} response.AgentConfigOverrideResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /agents/{id}/config-override [get]
+func (h *ControllerHandler) GetAgentConfigOverride(w http.ResponseWriter, r *http.Request) {
+	agentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid agent id", http.StatusBadRequest)
+		return
+	}
+
+	override, err := h.Service.GetAgentConfigOverride(r.Context(), agentID)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Config override not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get config override", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(override)
+}
+
+// Set Agent Config Override godoc
+// @Summary Set agent config override
+// @Description Replace the JSON merge patch (RFC 7386) applied to the namespace config for this agent. Omitted fields are inherited, null removes a field and an empty object clears the override. The merged config must pass the namespace schema. In namespaces listed in APPROVAL_REQUIRED_NAMESPACES the change is stored as a proposal of the agent's namespace instead, answered with 202, until another key approves it.
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Agent ID"
+// @Param body body object true "Config override"
+// @Success 200 {object} response.AgentConfigOverrideResponse
+// @Success 202 {object} response.AgentConfigOverrideResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /agents/{id}/config-override [put]
+func (h *ControllerHandler) SetAgentConfigOverride(w http.ResponseWriter, r *http.Request) {
+	agentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid agent id", http.StatusBadRequest)
+		return
+	}
+
+	var body request.AgentConfigOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	override, err := h.Service.SetAgentConfigOverride(r.Context(), agentID, body)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Agent not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidConfig) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to set config override", http.StatusInternalServerError)
+		return
+	}
+
+	if override.Proposal != nil {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(override)
+}
+
+// Clear Agent Config Override godoc
+// @Summary Clear agent config override
+// @Description Serve the namespace config to this agent again. In namespaces listed in APPROVAL_REQUIRED_NAMESPACES the change is stored as a proposal instead, answered with 202.
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Agent ID"
+// @Success 200 {object} response.AgentConfigOverrideResponse
+// @Success 202 {object} response.AgentConfigOverrideResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /agents/{id}/config-override [delete]
+func (h *ControllerHandler) ClearAgentConfigOverride(w http.ResponseWriter, r *http.Request) {
+	agentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid agent id", http.StatusBadRequest)
+		return
+	}
+
+	override, err := h.Service.SetAgentConfigOverride(r.Context(), agentID, request.AgentConfigOverrideRequest{})
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Agent not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to clear config override", http.StatusInternalServerError)
+		return
+	}
+
+	if override.Proposal != nil {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(override)
+}
+
+// agentNameFromCertificate binds the agent name to a verified client
+// certificate. Without a client certificate the name is kept.
+func agentNameFromCertificate(r *http.Request, body *request.RegisterAgentRequest) error {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return nil
+	}
+	return body.BindCertificateIdentity(tlsutil.CertificateIdentity(r.TLS.VerifiedChains[0][0]))
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"controller-service/internal/api/request"
+	"controller-service/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Create API Key godoc
+// @Summary Create API key
+// @Description Issue a named API key with scopes (config:read, config:write, agents:register, fleet:read, admin) and an optional expiry. The key is only returned in this response. Requires the admin scope.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body request.CreateAPIKeyRequest true "API key name, scopes and expiry"
+// @Success 201 {object} response.CreatedAPIKeyResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api-keys [post]
+func (h *ControllerHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var body request.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	apiKey, err := h.Service.CreateAPIKey(r.Context(), body)
+	if errors.Is(err, service.ErrAPIKeyExists) {
+		http.Error(w, "API key name already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to create API key", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(apiKey)
+}
+
+// List API Keys godoc
+// @Summary List API keys
+// @Description List every issued API key, revoked and expired ones included, without the keys themselves. Requires the admin scope.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.APIKeyListResponse
+// @Failure 500 {object} map[string]interface{}
+// @Router /api-keys [get]
+func (h *ControllerHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	apiKeys, err := h.Service.ListAPIKeys(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to list API keys", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(apiKeys)
+}
+
+// Revoke API Key godoc
+// @Summary Revoke API key
+// @Description Disable an API key for good. Requires the admin scope.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} response.APIKeyResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api-keys/{id} [delete]
+func (h *ControllerHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid API key id", http.StatusBadRequest)
+		return
+	}
+
+	apiKey, err := h.Service.RevokeAPIKey(r.Context(), id)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "API key not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to revoke API key", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(apiKey)
+}
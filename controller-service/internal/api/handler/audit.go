@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// List Audit Events godoc
+// @Summary List audit events
+// @Description List recorded config changes and agent registrations, newest first. since and until are RFC 3339 timestamps bounding the half-open range [since, until).
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param actor query string false "Only events of this actor"
+// @Param action query string false "Only events of this action, e.g. config.update"
+// @Param since query string false "Only events at or after this time (RFC 3339)"
+// @Param until query string false "Only events before this time (RFC 3339)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} response.AuditEventListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /audit [get]
+func (h *ControllerHandler) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := auditFilterFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pagination, err := paginationFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.Service.ListAuditEvents(r.Context(), filter, pagination)
+	if err != nil {
+		http.Error(w, "Failed to list audit events", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(events)
+}
+
+// Export Audit Events godoc
+// @Summary Export audit events
+// @Description Stream every matching audit event as newline-delimited JSON, oldest first, for ingestion by a SIEM. Takes the same filters as GET /audit.
+// @Tags audit
+// @Produce application/x-ndjson
+// @Security ApiKeyAuth
+// @Param actor query string false "Only events of this actor"
+// @Param action query string false "Only events of this action, e.g. config.update"
+// @Param since query string false "Only events at or after this time (RFC 3339)"
+// @Param until query string false "Only events before this time (RFC 3339)"
+// @Success 200 {object} response.AuditEventResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /audit/export [get]
+func (h *ControllerHandler) ExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := auditFilterFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	written := 0
+
+	err = h.Service.ExportAuditEvents(r.Context(), filter, func(event response.AuditEventResponse) error {
+		if written == 0 {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+		written++
+		if flusher != nil && written%100 == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil && written == 0 {
+		http.Error(w, "Failed to export audit events", http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		// the status is already sent, the client sees a truncated stream
+		slog.Error("ExportAuditEvents Failed to stream audit events", slog.Any("error", err), slog.Int("written", written))
+		return
+	}
+
+	if written == 0 {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func auditFilterFromQuery(r *http.Request) (request.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := request.AuditFilter{
+		Actor:  query.Get("actor"),
+		Action: query.Get("action"),
+	}
+
+	if since := query.Get("since"); since != "" {
+		value, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return filter, errors.New("since must be an RFC 3339 timestamp")
+		}
+		filter.Since = value.UTC()
+	}
+	if until := query.Get("until"); until != "" {
+		value, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return filter, errors.New("until must be an RFC 3339 timestamp")
+		}
+		filter.Until = value.UTC()
+	}
+
+	return filter, filter.Validate()
+}
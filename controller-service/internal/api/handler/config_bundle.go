@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"controller-service/internal/api/request"
+	"controller-service/internal/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// maxBundleBytes bounds the size of an imported bundle.
+const maxBundleBytes = 16 << 20
+
+// Export Config godoc
+// @Summary Export config
+// @Description Export every namespace with its schema, latest active document and version history, as JSON or, with format=yaml or an Accept header asking for YAML, as YAML. The bundle can be fed to POST /config/import.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Produce application/yaml
+// @Security ApiKeyAuth
+// @Param format query string false "json (default) or yaml"
+// @Success 200 {object} response.ConfigBundle
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/export [get]
+func (h *ControllerHandler) ExportConfig(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	switch {
+	case format == "" && strings.Contains(r.Header.Get("Accept"), "yaml"):
+		format = "yaml"
+	case format == "":
+		format = "json"
+	case format != "json" && format != "yaml":
+		http.Error(w, "format must be json or yaml", http.StatusBadRequest)
+		return
+	}
+
+	bundle, err := h.Service.ExportConfig(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to export config", http.StatusInternalServerError)
+		return
+	}
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(bundle)
+		return
+	}
+
+	bundleJSON, err := json.Marshal(bundle)
+	if err != nil {
+		http.Error(w, "Failed to export config", http.StatusInternalServerError)
+		return
+	}
+
+	bundleYAML, err := yaml.JSONToYAML(bundleJSON)
+	if err != nil {
+		http.Error(w, "Failed to export config", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(bundleYAML)
+}
+
+// Import Config godoc
+// @Summary Import config
+// @Description Apply a bundle as produced by GET /config/export, as JSON or, with a YAML Content-Type, as YAML. Each namespace gets the schema and document of the bundle; equal ones are left alone, so importing twice changes nothing. The version histories of the bundle are ignored. Documents are checked like POST /config and become proposals in namespaces requiring approval. Nothing is applied if any namespace fails.
+// @Tags config
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body request.ConfigImportRequest true "Config bundle"
+// @Success 200 {object} response.ConfigImportResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} response.ConfigConflictResponse
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/import [post]
+func (h *ControllerHandler) ImportConfig(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBundleBytes))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if strings.Contains(mediaType, "yaml") {
+		if body, err = yaml.YAMLToJSON(body); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var payload request.ConfigImportRequest
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := payload.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.Service.ImportConfig(r.Context(), payload)
+	if errors.Is(err, service.ErrInvalidConfig) || errors.Is(err, service.ErrInvalidSchema) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var conflictErr *service.VersionConflictError
+	if errors.As(err, &conflictErr) {
+		writeVersionConflict(w, conflictErr)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to import config", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(result)
+}
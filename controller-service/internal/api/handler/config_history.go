@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"controller-service/internal/api/request"
+	"controller-service/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// List Config Versions godoc
+// @Summary List config versions
+// @Description List every stored config version of the default namespace, newest first. Also served as /namespaces/{ns}/config/versions.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} response.ConfigVersionListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/versions [get]
+func (h *ControllerHandler) ListConfigVersions(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pagination, err := paginationFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	versions, err := h.Service.ListConfigVersions(r.Context(), namespace, pagination)
+	if err != nil {
+		http.Error(w, "Failed to list config versions", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(versions)
+}
+
+// Get Config Version godoc
+// @Summary Get config version
+// @Description Get a single config version of the default namespace. Also served as /namespaces/{ns}/config/versions/{version}.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param version path int true "Config version"
+// @Success 200 {object} response.ConfigVersionResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/versions/{version} [get]
+func (h *ControllerHandler) GetConfigVersion(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := versionFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.Service.GetConfigVersion(r.Context(), namespace, version)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Config version not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get config version", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(config)
+}
+
+// Diff Config Versions godoc
+// @Summary Diff config versions
+// @Description Field-level diff between two config versions of the default namespace. Also served as /namespaces/{ns}/config/diff.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param from query int true "Base config version"
+// @Param to query int true "Target config version"
+// @Success 200 {object} response.ConfigDiffResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/diff [get]
+func (h *ControllerHandler) DiffConfigVersions(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	from, err := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+	if err != nil {
+		http.Error(w, "from must be a number", http.StatusBadRequest)
+		return
+	}
+
+	to, err := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+	if err != nil {
+		http.Error(w, "to must be a number", http.StatusBadRequest)
+		return
+	}
+
+	body := request.ConfigDiffRequest{From: from, To: to}
+	if err := body.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.Service.DiffConfigVersions(r.Context(), namespace, body.From, body.To)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Config version not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to diff config versions", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(diff)
+}
+
+// Rollback Config godoc
+// @Summary Rollback config
+// @Description Create a new config version that copies an older one, in the default namespace. In namespaces listed in APPROVAL_REQUIRED_NAMESPACES the rollback is stored as a proposal instead, answered with 202, until another key approves it. Also served as /namespaces/{ns}/config/rollback/{version}.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param version path int true "Config version to roll back to"
+// @Success 200 {object} response.ConfigVersionResponse
+// @Success 202 {object} response.ConfigVersionResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} response.ConfigConflictResponse
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/rollback/{version} [post]
+func (h *ControllerHandler) RollbackConfig(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := versionFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.Service.RollbackConfig(r.Context(), namespace, version)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Config version not found", http.StatusNotFound)
+		return
+	}
+	var conflictErr *service.VersionConflictError
+	if errors.As(err, &conflictErr) {
+		writeVersionConflict(w, conflictErr)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to rollback config", http.StatusInternalServerError)
+		return
+	}
+
+	if config.Proposal != nil {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(config)
+}
+
+func versionFromPath(r *http.Request) (int64, error) {
+	version, err := strconv.ParseInt(r.PathValue("version"), 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errors.New("version must be a positive number")
+	}
+	return version, nil
+}
+
+func paginationFromQuery(r *http.Request) (request.PaginationRequest, error) {
+	pagination := request.PaginationRequest{
+		Page:     1,
+		PageSize: request.DefaultPageSize,
+	}
+
+	query := r.URL.Query()
+	if page := query.Get("page"); page != "" {
+		value, err := strconv.Atoi(page)
+		if err != nil {
+			return pagination, errors.New("page must be a number")
+		}
+		pagination.Page = value
+	}
+	if pageSize := query.Get("page_size"); pageSize != "" {
+		value, err := strconv.Atoi(pageSize)
+		if err != nil {
+			return pagination, errors.New("page_size must be a number")
+		}
+		pagination.PageSize = value
+	}
+
+	return pagination, pagination.Validate()
+}
@@ -1,20 +1,33 @@
 package handler
 
 import (
+	"context"
 	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
 	"controller-service/internal/service"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type ControllerHandler struct {
 	Service service.IControllerService
+	// WatchTimeout bounds how long GET /config/watch holds a request open.
+	WatchTimeout time.Duration
+	// StreamKeepAlive is how often GET /config/stream writes a keep-alive
+	// comment while the config does not change.
+	StreamKeepAlive time.Duration
+	// Draining is closed when the server shuts down, ending open config
+	// streams; clients resume elsewhere with Last-Event-ID.
+	Draining <-chan struct{}
 }
 
 // Register Agent godoc
 // @Summary Registe agent
-// @Description Register a new agent with a name
+// @Description Register a new agent with a name, an optional namespace (defaults to "default") and optional labels for rollout selectors. With a verified client certificate the name defaults to, and must match, the certificate's CN or SAN.
 // @Tags agents
 // @Accept json
 // @Produce json
@@ -22,6 +35,7 @@ type ControllerHandler struct {
 // @Param body body request.RegisterAgentRequest true "Agent registration data"
 // @Success 200 {object} response.ConfigResponse
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /register [post]
 func (h *ControllerHandler) Register(w http.ResponseWriter, r *http.Request) {
@@ -31,7 +45,17 @@ func (h *ControllerHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	agent, err := h.Service.RegisterAgent(r.Context(), body.Name)
+	if err := body.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := agentNameFromCertificate(r, &body); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	agent, err := h.Service.RegisterAgent(r.Context(), body)
 	if err != nil {
 		http.Error(w, "Failed to register agent", http.StatusInternalServerError)
 		return
@@ -42,36 +66,61 @@ func (h *ControllerHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 // Get Config godoc
 // @Summary Get config
-// @Description Get config
+// @Description Get the latest config of the calling agent's namespace (X-Agent-ID), or of the default namespace, with the agent's config override applied. Agents selected by a canary rollout get the canary version. The Version header changes when either layer changes; the ETag header holds the namespace config version, for If-Match on POST /config. With a signing key, signature holds the Ed25519 signature of the Version header and config. Also served as /namespaces/{ns}/config.
 // @Tags config
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Param X-Agent-ID header string false "Calling agent ID"
 // @Success 200 {object} response.ConfigResponse
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /config [get]
 func (h *ControllerHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
-	config, version, err := h.Service.GetConfig(r.Context())
+	namespace, err := namespaceFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	config, version, err := h.Service.GetConfig(r.Context(), namespace, r.Header.Get("X-Agent-ID"))
+	if errors.Is(err, service.ErrInvalidAgentID) {
+		http.Error(w, "Invalid agent id", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, service.ErrAgentForbidden) {
+		http.Error(w, "Agent is registered by another identity", http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Config not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to get config", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Version", fmt.Sprint(version))
+	w.Header().Set("ETag", response.ETag(config.GlobalVersion))
 	json.NewEncoder(w).Encode(config)
 }
 
 // Update Config godoc
 // @Summary Update config
-// @Description Update config
+// @Description Publish a new config document in the default namespace. The document must have url and poll_interval and pass the namespace schema; the reserved rollout and expected_version keys are not part of it. With a rollout policy the version is served only to the selected agents until promoted. A new version aborts any canary still rolling out. With If-Match or expected_version the update is only applied if the namespace is still at that version. With effective_at the update is scheduled instead and committed as a new version by the controller at that time; the response is then 202 with the scheduled_id, and its version and ETag name the current version. In namespaces listed in APPROVAL_REQUIRED_NAMESPACES the update is stored as a proposal instead, answered with 202, until another key approves it. Also served as /namespaces/{ns}/config.
 // @Tags config
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
-// @Param body body request.UpdateConfigRequest true "Config update data"
-// @Success 200 {object} map[string]interface{}
+// @Param If-Match header string false "ETag of the config version the update is based on"
+// @Param body body object true "Config document"
+// @Success 200 {object} response.ConfigUpdateResponse
+// @Success 202 {object} response.ConfigUpdateResponse
 // @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} response.ConfigConflictResponse
 // @Failure 500 {object} map[string]interface{}
 // @Router /config [post]
 func (h *ControllerHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
@@ -81,15 +130,253 @@ func (h *ControllerHandler) UpdateConfig(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	ifMatch, err := request.ParseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ifMatch != nil {
+		if body.ExpectedVersion != nil && *body.ExpectedVersion != *ifMatch {
+			http.Error(w, "If-Match and expected_version disagree", http.StatusBadRequest)
+			return
+		}
+		body.ExpectedVersion = ifMatch
+	}
+
 	if err := body.Validate(); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := h.Service.UpdateConfig(r.Context(), body); err != nil {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.Service.UpdateConfig(r.Context(), namespace, body)
+	if errors.Is(err, service.ErrInvalidConfig) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var conflictErr *service.VersionConflictError
+	if errors.As(err, &conflictErr) {
+		writeVersionConflict(w, conflictErr)
+		return
+	}
+	if err != nil {
 		http.Error(w, "Failed to update config", http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.Header().Set("ETag", config.ETag)
+	if config.ScheduledID != "" || config.Proposal != nil {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(config)
+}
+
+// writeVersionConflict answers 409 with the version the caller should
+// re-read before retrying.
+func writeVersionConflict(w http.ResponseWriter, err *service.VersionConflictError) {
+	etag := response.ETag(err.CurrentVersion)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(response.ConfigConflictResponse{
+		Error:          err.Error(),
+		CurrentVersion: err.CurrentVersion,
+		ETag:           etag,
+	})
+}
+
+// Watch Config godoc
+// @Summary Watch config
+// @Description Long-poll until the config version differs from since_version. Responds 304 when the timeout expires first. Namespace resolution follows GET /config. Also served as /namespaces/{ns}/config/watch.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param X-Agent-ID header string false "Calling agent ID"
+// @Param since_version query int true "Config version the caller already has"
+// @Param timeout query int false "Seconds to wait before giving up (capped by the server)"
+// @Success 200 {object} response.ConfigResponse
+// @Success 304
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/watch [get]
+func (h *ControllerHandler) WatchConfig(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sinceVersion, err := strconv.Atoi(r.URL.Query().Get("since_version"))
+	if err != nil {
+		http.Error(w, "since_version must be a number", http.StatusBadRequest)
+		return
+	}
+
+	timeout := h.WatchTimeout
+	if timeoutParam := r.URL.Query().Get("timeout"); timeoutParam != "" {
+		seconds, err := strconv.Atoi(timeoutParam)
+		if err != nil || seconds <= 0 {
+			http.Error(w, "timeout must be a positive number", http.StatusBadRequest)
+			return
+		}
+		if requested := time.Duration(seconds) * time.Second; requested < timeout {
+			timeout = requested
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	config, version, err := h.Service.WatchConfig(ctx, namespace, r.Header.Get("X-Agent-ID"), sinceVersion)
+	if errors.Is(err, service.ErrInvalidAgentID) {
+		http.Error(w, "Invalid agent id", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, service.ErrAgentForbidden) {
+		http.Error(w, "Agent is registered by another identity", http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, service.ErrNotModified) {
+		w.Header().Set("Version", fmt.Sprint(version))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to watch config", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Version", fmt.Sprint(version))
+	json.NewEncoder(w).Encode(config)
+}
+
+// Stream Config godoc
+// @Summary Stream config
+// @Description Server-sent events: a "config" event, with the served config as data and its version as id, whenever the version changes, and keep-alive comments in between. The current config is sent first unless Last-Event-ID already holds its version, so a client reconnecting with Last-Event-ID misses no change. Namespace resolution follows GET /config. Also served as /namespaces/{ns}/config/stream.
+// @Tags config
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Param X-Agent-ID header string false "Calling agent ID"
+// @Param Last-Event-ID header int false "Config version the caller already has"
+// @Success 200 {object} response.ConfigResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/stream [get]
+func (h *ControllerHandler) StreamConfig(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var sinceVersion int
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		sinceVersion, err = strconv.Atoi(lastEventID)
+		if err != nil {
+			http.Error(w, "Last-Event-ID must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// the response starts with the first event or keep-alive, so errors from
+	// the first lookup still get a status code
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	agentID := r.Header.Get("X-Agent-ID")
+	for {
+		ctx, cancel := context.WithTimeout(r.Context(), h.StreamKeepAlive)
+		config, version, err := h.Service.WatchConfig(ctx, namespace, agentID, sinceVersion)
+		cancel()
+
+		if r.Context().Err() != nil {
+			return
+		}
+		select {
+		case <-h.Draining:
+			return
+		default:
+		}
+		if errors.Is(err, service.ErrNotModified) {
+			start()
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+			continue
+		}
+		if errors.Is(err, service.ErrInvalidAgentID) {
+			if !started {
+				http.Error(w, "Invalid agent id", http.StatusBadRequest)
+			}
+			return
+		}
+		if errors.Is(err, service.ErrAgentForbidden) {
+			if !started {
+				http.Error(w, "Agent is registered by another identity", http.StatusForbidden)
+			}
+			return
+		}
+		if err != nil {
+			if !started {
+				http.Error(w, "Failed to stream config", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		data, err := json.Marshal(config)
+		if err != nil {
+			if !started {
+				http.Error(w, "Failed to stream config", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		// compact JSON holds no newline, so it fits on a single data line
+		start()
+		fmt.Fprintf(w, "id: %d\nevent: config\ndata: %s\n\n", version, data)
+		flusher.Flush()
+		sinceVersion = version
+	}
+}
+
+// namespaceFromPath returns the {ns} segment of /namespaces/{ns}/... routes,
+// or an empty string on the un-namespaced routes.
+func namespaceFromPath(r *http.Request) (string, error) {
+	namespace := r.PathValue("ns")
+	if namespace == "" {
+		return "", nil
+	}
+	return namespace, request.ValidateNamespace(namespace)
+}
+
+// namespaceOrDefault is namespaceFromPath falling back to the default
+// namespace.
+func namespaceOrDefault(r *http.Request) (string, error) {
+	namespace, err := namespaceFromPath(r)
+	if namespace == "" && err == nil {
+		namespace = request.DefaultNamespace
+	}
+	return namespace, err
 }
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"controller-service/internal/api/request"
+	"controller-service/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// List Config Proposals godoc
+// @Summary List config proposals
+// @Description List the config updates proposed in the default namespace, newest first. Also served as /namespaces/{ns}/config/proposals.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Filter by status: pending, approved or expired"
+// @Success 200 {object} response.ConfigProposalListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/proposals [get]
+func (h *ControllerHandler) ListConfigProposals(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := request.ConfigProposalFilter{Status: r.URL.Query().Get("status")}
+	if err := filter.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proposals, err := h.Service.ListConfigProposals(r.Context(), namespace, filter)
+	if err != nil {
+		http.Error(w, "Failed to list config proposals", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(proposals)
+}
+
+// Approve Config Proposal godoc
+// @Summary Approve config proposal
+// @Description Apply a pending config proposal of the default namespace as if it was sent now: a config update, a rollback, an agent config override, a schema, a promotion, an abort or the cancellation of a scheduled config. The approving key must differ from the proposing one, must not have created it or been created by it, directly or through other keys, and must not share its creator unless that is the bootstrap key. Also served as /namespaces/{ns}/config/proposals/{id}/approve.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Proposal ID"
+// @Success 200 {object} response.ConfigUpdateResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/proposals/{id}/approve [post]
+func (h *ControllerHandler) ApproveConfigProposal(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid proposal id", http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.Service.ApproveConfigProposal(r.Context(), namespace, id)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Proposal not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrSelfApproval) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, service.ErrProposalNotPending) || errors.Is(err, service.ErrProposalExpired) || errors.Is(err, service.ErrProposalAgentGone) || errors.Is(err, service.ErrProposalScheduleGone) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, service.ErrRolloutNotInProgress) {
+		http.Error(w, "Config version is not rolling out", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidConfig) || errors.Is(err, service.ErrInvalidSchema) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var conflictErr *service.VersionConflictError
+	if errors.As(err, &conflictErr) {
+		writeVersionConflict(w, conflictErr)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to approve proposal", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", config.ETag)
+	json.NewEncoder(w).Encode(config)
+}
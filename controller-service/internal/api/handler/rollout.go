@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"controller-service/internal/api/request"
+	"controller-service/internal/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// Get Config Rollout godoc
+// @Summary Get config rollout
+// @Description Report which agents of the default namespace have applied a config version, which ones lag and how long propagation took. Also served as /namespaces/{ns}/config/versions/{version}/rollout.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param version path int true "Config version"
+// @Success 200 {object} response.ConfigRolloutResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/versions/{version}/rollout [get]
+func (h *ControllerHandler) GetConfigRollout(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := versionFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rollout, err := h.Service.GetConfigRollout(r.Context(), namespace, version)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Config version not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get config rollout", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(rollout)
+}
+
+// Promote Config Version godoc
+// @Summary Promote config version
+// @Description Widen the canary rollout of a config version to a percentage of the selected agents, or make it live for every agent when the percentage is omitted or 100. In namespaces listed in APPROVAL_REQUIRED_NAMESPACES the promotion is stored as a proposal instead, answered with 202, until another key approves it. Also served as /namespaces/{ns}/config/versions/{version}/promote.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param version path int true "Config version"
+// @Param body body request.PromoteRolloutRequest false "Rollout percentage"
+// @Success 200 {object} response.ConfigVersionResponse
+// @Success 202 {object} response.ConfigVersionResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/versions/{version}/promote [post]
+func (h *ControllerHandler) PromoteConfigVersion(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := versionFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var body request.PromoteRolloutRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.Service.PromoteConfigVersion(r.Context(), namespace, version, body.Percentage)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Config version not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrRolloutNotInProgress) {
+		http.Error(w, "Config version is not rolling out", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to promote config version", http.StatusInternalServerError)
+		return
+	}
+
+	if config.Proposal != nil {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(config)
+}
+
+// Abort Config Version godoc
+// @Summary Abort config version
+// @Description Cancel the canary rollout of a config version; its agents go back to the latest active version. In namespaces listed in APPROVAL_REQUIRED_NAMESPACES the abort is stored as a proposal instead, answered with 202, until another key approves it. Also served as /namespaces/{ns}/config/versions/{version}/abort.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param version path int true "Config version"
+// @Success 200 {object} response.ConfigVersionResponse
+// @Success 202 {object} response.ConfigVersionResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/versions/{version}/abort [post]
+func (h *ControllerHandler) AbortConfigVersion(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := versionFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	config, err := h.Service.AbortConfigVersion(r.Context(), namespace, version)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Config version not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrRolloutNotInProgress) {
+		http.Error(w, "Config version is not rolling out", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to abort config version", http.StatusInternalServerError)
+		return
+	}
+
+	if config.Proposal != nil {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(config)
+}
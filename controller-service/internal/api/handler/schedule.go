@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"controller-service/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// List Scheduled Configs godoc
+// @Summary List scheduled configs
+// @Description List the config updates of the default namespace waiting for their effective_at, the next to be activated first. They take a version only when activated. Also served as /namespaces/{ns}/config/scheduled.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.ScheduledConfigListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/scheduled [get]
+func (h *ControllerHandler) ListScheduledConfigs(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scheduled, err := h.Service.ListScheduledConfigs(r.Context(), namespace)
+	if err != nil {
+		http.Error(w, "Failed to list scheduled configs", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(scheduled)
+}
+
+// Cancel Scheduled Config godoc
+// @Summary Cancel scheduled config
+// @Description Delete a scheduled config of the default namespace before it is activated. In namespaces listed in APPROVAL_REQUIRED_NAMESPACES the cancellation is stored as a proposal instead, answered with 202, until another key approves it. Also served as /namespaces/{ns}/config/scheduled/{id}.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Scheduled config ID"
+// @Success 200 {object} response.ScheduledConfigResponse
+// @Success 202 {object} response.ScheduledConfigResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/scheduled/{id} [delete]
+func (h *ControllerHandler) CancelScheduledConfig(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid scheduled config id", http.StatusBadRequest)
+		return
+	}
+
+	scheduled, err := h.Service.CancelScheduledConfig(r.Context(), namespace, id)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Scheduled config not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to cancel scheduled config", http.StatusInternalServerError)
+		return
+	}
+
+	if scheduled.Proposal != nil {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(scheduled)
+}
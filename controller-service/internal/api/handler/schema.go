@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"controller-service/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Get Config Schema godoc
+// @Summary Get config schema
+// @Description Get the JSON Schema config documents of the default namespace are validated against. Also served as /namespaces/{ns}/config/schema.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.ConfigSchemaResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/schema [get]
+func (h *ControllerHandler) GetConfigSchema(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	schema, err := h.Service.GetConfigSchema(r.Context(), namespace)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Config schema not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get config schema", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(schema)
+}
+
+// Set Config Schema godoc
+// @Summary Set config schema
+// @Description Register the JSON Schema that new config documents of the default namespace must pass, on top of the built-in url and poll_interval checks. In namespaces listed in APPROVAL_REQUIRED_NAMESPACES the schema is stored as a proposal instead, answered with 202, until another key approves it. Also served as /namespaces/{ns}/config/schema.
+// @Tags config
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body object true "JSON Schema"
+// @Success 200 {object} response.ConfigSchemaResponse
+// @Success 202 {object} response.ConfigSchemaResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /config/schema [put]
+func (h *ControllerHandler) SetConfigSchema(w http.ResponseWriter, r *http.Request) {
+	namespace, err := namespaceOrDefault(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	schema, err := h.Service.SetConfigSchema(r.Context(), namespace, body)
+	if errors.Is(err, service.ErrInvalidSchema) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to set config schema", http.StatusInternalServerError)
+		return
+	}
+
+	if schema.Proposal != nil {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(schema)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"controller-service/internal/api/request"
+	"controller-service/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Create Webhook godoc
+// @Summary Create webhook
+// @Description Subscribe a URL to config change events of the given namespaces, or of every namespace when none are given. Events are signed with a secret that is only returned in this response. Requires the admin scope.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param body body request.WebhookRequest true "Webhook URL, namespaces and enabled flag"
+// @Success 201 {object} response.CreatedWebhookResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /webhooks [post]
+func (h *ControllerHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var body request.WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := h.Service.CreateWebhook(r.Context(), body)
+	if err != nil {
+		http.Error(w, "Failed to create webhook", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// List Webhooks godoc
+// @Summary List webhooks
+// @Description List every webhook, without its secret. Requires the admin scope.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.WebhookListResponse
+// @Failure 500 {object} map[string]interface{}
+// @Router /webhooks [get]
+func (h *ControllerHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.Service.ListWebhooks(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to list webhooks", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(webhooks)
+}
+
+// Get Webhook godoc
+// @Summary Get webhook
+// @Description Get a webhook, without its secret. Requires the admin scope.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} response.WebhookResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /webhooks/{id} [get]
+func (h *ControllerHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook id", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := h.Service.GetWebhook(r.Context(), id)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get webhook", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// Update Webhook godoc
+// @Summary Update webhook
+// @Description Replace the URL, namespaces and enabled flag of a webhook. The secret is kept, and pending deliveries go to the new URL. Requires the admin scope.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Param body body request.WebhookRequest true "Webhook URL, namespaces and enabled flag"
+// @Success 200 {object} response.WebhookResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /webhooks/{id} [put]
+func (h *ControllerHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook id", http.StatusBadRequest)
+		return
+	}
+
+	var body request.WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := h.Service.UpdateWebhook(r.Context(), id, body)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to update webhook", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// Delete Webhook godoc
+// @Summary Delete webhook
+// @Description Delete a webhook and its deliveries, pending ones included. Requires the admin scope.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} response.WebhookResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /webhooks/{id} [delete]
+func (h *ControllerHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook id", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := h.Service.DeleteWebhook(r.Context(), id)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to delete webhook", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// List Webhook Deliveries godoc
+// @Summary List webhook deliveries
+// @Description List the deliveries of a webhook, newest first, with their attempts, last response status and error. Requires the admin scope.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Only deliveries in this status: pending, delivered or failed"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} response.WebhookDeliveryListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /webhooks/{id}/deliveries [get]
+func (h *ControllerHandler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook id", http.StatusBadRequest)
+		return
+	}
+
+	filter := request.WebhookDeliveryFilter{Status: r.URL.Query().Get("status")}
+	if err := filter.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pagination, err := paginationFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	deliveries, err := h.Service.ListWebhookDeliveries(r.Context(), id, filter, pagination)
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to list webhook deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(deliveries)
+}
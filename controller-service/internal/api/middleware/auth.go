@@ -1,17 +1,74 @@
 package middleware
 
 import (
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	"controller-service/internal/requestctx"
+	"controller-service/internal/service"
+	"controller-service/internal/tlsutil"
+	"crypto/subtle"
+	"errors"
 	"net/http"
 )
 
-func APIKeyAuth(apiKey string) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
+// APIKeyAuthenticator resolves a plaintext API key to the key it was issued
+// as, returning service.ErrNotFound for unknown, revoked or expired keys.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*response.APIKeyResponse, error)
+}
+
+// ErrUnauthorized is returned by Authenticate for a missing or unknown key.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// APIKeyAuth returns a middleware factory admitting requests whose X-API-Key
+// holds the given scope. The bootstrap key (API_KEY) has the admin scope so
+// a fresh deployment can create the first keys; it is disabled when empty.
+func APIKeyAuth(bootstrapKey string, keys APIKeyAuthenticator) func(scope string, next http.Handler) http.Handler {
+	return func(scope string, next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.Header.Get("X-API-Key") != apiKey {
+			name, scopes, err := Authenticate(r.Context(), bootstrapKey, keys, r.Header.Get("X-API-Key"))
+			if errors.Is(err, ErrUnauthorized) {
 				http.Error(w, "unauthorized", http.StatusUnauthorized)
 				return
 			}
-			next.ServeHTTP(w, r)
+			if err != nil {
+				http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+				return
+			}
+
+			if !request.HasScope(scopes, scope) {
+				http.Error(w, "forbidden: requires scope "+scope, http.StatusForbidden)
+				return
+			}
+
+			info := requestctx.FromContext(r.Context())
+			info.Actor = "api-key:" + name
+			if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+				info.Certificate = tlsutil.CertificateIdentity(r.TLS.VerifiedChains[0][0])
+			}
+			next.ServeHTTP(w, r.WithContext(requestctx.WithInfo(r.Context(), info)))
 		})
 	}
 }
+
+// Authenticate resolves an API key to its name and scopes, treating the
+// bootstrap key as an admin key. It is shared by the REST and gRPC APIs.
+func Authenticate(ctx context.Context, bootstrapKey string, keys APIKeyAuthenticator, key string) (string, []string, error) {
+	if key == "" {
+		return "", nil, ErrUnauthorized
+	}
+
+	if bootstrapKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(bootstrapKey)) == 1 {
+		return request.BootstrapAPIKeyName, []string{request.ScopeAdmin}, nil
+	}
+
+	apiKey, err := keys.AuthenticateAPIKey(ctx, key)
+	if errors.Is(err, service.ErrNotFound) {
+		return "", nil, ErrUnauthorized
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return apiKey.Name, apiKey.Scopes, nil
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"controller-service/internal/requestctx"
+	"net"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestInfo tags every request with its source IP and a request ID. The ID
+// is taken from the X-Request-ID header when the caller sent a sane one,
+// generated otherwise, and echoed in the X-Request-ID response header.
+func RequestInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := RequestID(r.Header.Get("X-Request-ID"))
+		w.Header().Set("X-Request-ID", requestID)
+
+		sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			sourceIP = r.RemoteAddr
+		}
+
+		ctx := requestctx.WithInfo(r.Context(), requestctx.Info{
+			SourceIP:  sourceIP,
+			RequestID: requestID,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestID returns the request ID a caller sent when it is sane, and a new
+// one otherwise.
+func RequestID(sent string) string {
+	if requestIDPattern.MatchString(sent) {
+		return sent
+	}
+	return uuid.NewString()
+}
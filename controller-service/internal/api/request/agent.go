@@ -0,0 +1,36 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+type AgentHeartbeatRequest struct {
+	// AppliedVersion is the namespace config version the agent's workers
+	// applied, 0 while they have none from the controller yet.
+	AppliedVersion int64             `json:"applied_version"`
+	WorkerHealthy  bool              `json:"worker_healthy"`
+	BuildInfo      map[string]string `json:"build_info"`
+}
+
+func (r AgentHeartbeatRequest) Validate() error {
+	if r.AppliedVersion < 0 {
+		return errors.New("applied_version must not be negative")
+	}
+	return nil
+}
+
+// AgentConfigOverrideRequest is a JSON merge patch (RFC 7386) applied to the
+// namespace config for a single agent: present keys replace the namespace
+// values, nested objects are merged and null removes a key.
+type AgentConfigOverrideRequest struct {
+	Config json.RawMessage
+}
+
+func (r *AgentConfigOverrideRequest) UnmarshalJSON(data []byte) error {
+	if _, err := decodeObject(data); err != nil {
+		return err
+	}
+	r.Config = append(json.RawMessage(nil), data...)
+	return nil
+}
@@ -0,0 +1,61 @@
+package request
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"time"
+)
+
+const (
+	ScopeConfigRead     = "config:read"
+	ScopeConfigWrite    = "config:write"
+	ScopeAgentsRegister = "agents:register"
+	// ScopeFleetRead lists the registered agents, which config:read alone
+	// does not, so an agent key cannot enumerate the rest of the fleet.
+	ScopeFleetRead = "fleet:read"
+	// ScopeAdmin grants every other scope, manages API keys and webhooks, and
+	// reads the audit log.
+	ScopeAdmin = "admin"
+
+	// BootstrapAPIKeyName names the key configured through API_KEY, which
+	// always has the admin scope.
+	BootstrapAPIKeyName = "bootstrap"
+)
+
+var (
+	scopes         = []string{ScopeConfigRead, ScopeConfigWrite, ScopeAgentsRegister, ScopeFleetRead, ScopeAdmin}
+	apiKeyNameRule = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]{0,62})$`)
+)
+
+// HasScope reports whether a key with the given scopes may act with scope.
+func HasScope(granted []string, scope string) bool {
+	return slices.Contains(granted, scope) || slices.Contains(granted, ScopeAdmin)
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r CreateAPIKeyRequest) Validate() error {
+	if !apiKeyNameRule.MatchString(r.Name) {
+		return errors.New("name must be 1-63 letters, digits, '.', '_' or '-'")
+	}
+	if r.Name == BootstrapAPIKeyName {
+		return errors.New("name bootstrap is reserved")
+	}
+	if len(r.Scopes) == 0 {
+		return errors.New("scopes must not be empty")
+	}
+	for _, scope := range r.Scopes {
+		if !slices.Contains(scopes, scope) {
+			return errors.New("scopes must be config:read, config:write, agents:register, fleet:read or admin")
+		}
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package request
+
+import (
+	"errors"
+	"time"
+)
+
+// AuditFilter narrows audit events down by actor, action and a half-open
+// time range [Since, Until). Zero fields do not filter.
+type AuditFilter struct {
+	Actor  string
+	Action string
+	Since  time.Time
+	Until  time.Time
+}
+
+func (r AuditFilter) Validate() error {
+	if !r.Since.IsZero() && !r.Until.IsZero() && !r.Since.Before(r.Until) {
+		return errors.New("since must be before until")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ConfigImportRequest is a config bundle, as produced by GET /config/export.
+// The config and schema of each namespace are applied; the version history
+// of an exported bundle is ignored.
+type ConfigImportRequest struct {
+	Namespaces []ConfigImportNamespace `json:"namespaces"`
+}
+
+// ConfigImportNamespace is the desired state of a namespace. A nil Config or
+// Schema leaves it as it is.
+type ConfigImportNamespace struct {
+	Namespace string          `json:"namespace"`
+	Schema    json.RawMessage `json:"schema,omitempty" swaggertype:"object"`
+	Config    json.RawMessage `json:"config,omitempty" swaggertype:"object"`
+}
+
+func (r ConfigImportRequest) Validate() error {
+	if len(r.Namespaces) == 0 {
+		return errors.New("namespaces must not be empty")
+	}
+
+	seen := make(map[string]bool, len(r.Namespaces))
+	for _, namespace := range r.Namespaces {
+		if err := ValidateNamespace(namespace.Namespace); err != nil {
+			return err
+		}
+		if seen[namespace.Namespace] {
+			return fmt.Errorf("namespace %q is listed twice", namespace.Namespace)
+		}
+		seen[namespace.Namespace] = true
+
+		if err := namespace.Validate(); err != nil {
+			return fmt.Errorf("namespace %q: %w", namespace.Namespace, err)
+		}
+	}
+	return nil
+}
+
+func (r ConfigImportNamespace) Validate() error {
+	if r.Config == nil && r.Schema == nil {
+		return errors.New("config or schema is required")
+	}
+	if r.Schema != nil {
+		if _, err := decodeObject(r.Schema); err != nil {
+			return errors.New("schema must be a JSON object")
+		}
+	}
+	if r.Config != nil {
+		fields, err := decodeObject(r.Config)
+		if err != nil {
+			return errors.New("config must be a JSON object")
+		}
+		// an imported document is the desired state, it cannot roll out or
+		// be scheduled
+		for _, reserved := range []string{"rollout", "expected_version", "effective_at"} {
+			if _, ok := fields[reserved]; ok {
+				return fmt.Errorf("config must not hold the reserved %s key", reserved)
+			}
+		}
+	}
+	return nil
+}
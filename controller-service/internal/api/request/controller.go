@@ -1,22 +1,255 @@
 package request
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+
+	// DefaultNamespace holds the config of agents that did not pick one.
+	DefaultNamespace = "default"
+)
+
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]{0,61}[a-z0-9])?$`)
+
+func ValidateNamespace(namespace string) error {
+	if !namespacePattern.MatchString(namespace) {
+		return errors.New("namespace must be 1-63 lowercase letters, digits, '-' or '_'")
+	}
+	return nil
+}
 
 type RegisterAgentRequest struct {
-	Name string `json:"name"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels"`
+}
+
+func (r RegisterAgentRequest) Validate() error {
+	if r.Namespace != "" {
+		if err := ValidateNamespace(r.Namespace); err != nil {
+			return err
+		}
+	}
+	for key := range r.Labels {
+		if key == "" {
+			return errors.New("label keys must not be empty")
+		}
+	}
+	return nil
+}
+
+// BindCertificateIdentity ties the agent name to the identity of a verified
+// client certificate: an empty name takes the identity, a different one is
+// rejected. An empty identity leaves the name as sent.
+func (r *RegisterAgentRequest) BindCertificateIdentity(identity string) error {
+	switch {
+	case identity == "":
+	case r.Name == "":
+		r.Name = identity
+	case r.Name != identity:
+		return fmt.Errorf("agent name %q does not match client certificate %q", r.Name, identity)
+	}
+	return nil
 }
 
+// UpdateConfigRequest is a config document. Every top-level key except the
+// reserved rollout, expected_version and effective_at keys is part of
+// Config; the document itself is validated against the namespace schema by
+// the service.
+//
+// ExpectedVersion, when set, is the latest version of the namespace the
+// caller based the update on; 0 means the namespace has no version yet.
+// EffectiveAt, when set, schedules the version instead of committing it.
 type UpdateConfigRequest struct {
-	URL          string `json:"url"`
-	PollInterval int    `json:"poll_interval"`
+	Config          json.RawMessage
+	Rollout         *RolloutPolicy
+	ExpectedVersion *int64
+	EffectiveAt     *time.Time
+}
+
+func (r *UpdateConfigRequest) UnmarshalJSON(data []byte) error {
+	fields, err := decodeObject(data)
+	if err != nil {
+		return err
+	}
+
+	if rollout, ok := fields["rollout"]; ok {
+		if err := json.Unmarshal(rollout, &r.Rollout); err != nil {
+			return err
+		}
+		delete(fields, "rollout")
+	}
+
+	if expectedVersion, ok := fields["expected_version"]; ok {
+		if err := json.Unmarshal(expectedVersion, &r.ExpectedVersion); err != nil {
+			return errors.New("expected_version must be a number")
+		}
+		delete(fields, "expected_version")
+	}
+
+	if effectiveAt, ok := fields["effective_at"]; ok {
+		if err := json.Unmarshal(effectiveAt, &r.EffectiveAt); err != nil {
+			return errors.New("effective_at must be an RFC 3339 timestamp")
+		}
+		delete(fields, "effective_at")
+	}
+
+	r.Config, err = json.Marshal(fields)
+	return err
+}
+
+// MarshalJSON encodes the request as the document it was decoded from, with
+// the reserved keys merged back in.
+func (r UpdateConfigRequest) MarshalJSON() ([]byte, error) {
+	fields, err := decodeObject(r.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Rollout != nil {
+		if fields["rollout"], err = json.Marshal(r.Rollout); err != nil {
+			return nil, err
+		}
+	}
+	if r.ExpectedVersion != nil {
+		if fields["expected_version"], err = json.Marshal(r.ExpectedVersion); err != nil {
+			return nil, err
+		}
+	}
+	if r.EffectiveAt != nil {
+		if fields["effective_at"], err = json.Marshal(r.EffectiveAt); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(fields)
 }
 
 func (r UpdateConfigRequest) Validate() error {
-	if r.URL == "" {
-		return errors.New("url is required")
+	if r.ExpectedVersion != nil && *r.ExpectedVersion < 0 {
+		return errors.New("expected_version must not be negative")
+	}
+	if r.EffectiveAt != nil && !r.EffectiveAt.After(time.Now()) {
+		return errors.New("effective_at must be in the future")
+	}
+	if r.Rollout != nil {
+		return r.Rollout.Validate()
+	}
+	return nil
+}
+
+// ParseIfMatch reads the config version from an If-Match header holding a
+// single entity tag as returned in the ETag header ("3"); a bare number is
+// accepted too. An empty header or "*" sets no precondition and returns nil.
+func ParseIfMatch(header string) (*int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+	if err != nil || version < 0 {
+		return nil, errors.New("If-Match must be a config version ETag")
+	}
+	return &version, nil
+}
+
+// decodeObject decodes a JSON object, keeping its values undecoded.
+func decodeObject(data []byte) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, errors.New("body must be a JSON object")
+	}
+	if fields == nil {
+		return nil, errors.New("body must be a JSON object")
+	}
+	return fields, nil
+}
+
+// RolloutPolicy limits a new config version to the agents whose labels match
+// Selector and whose hash bucket falls within Percentage. A percentage of 0
+// selects every matching agent.
+type RolloutPolicy struct {
+	Percentage int               `json:"percentage"`
+	Selector   map[string]string `json:"selector,omitempty"`
+}
+
+func (r RolloutPolicy) Validate() error {
+	if r.Percentage < 0 || r.Percentage > 100 {
+		return errors.New("rollout percentage must be between 0 and 100")
+	}
+	if r.Percentage == 0 && len(r.Selector) == 0 {
+		return errors.New("rollout needs a percentage or a selector")
 	}
-	if r.PollInterval <= 0 {
-		return errors.New("poll_interval must be greater than 0")
+	return nil
+}
+
+// PromoteRolloutRequest widens a canary rollout to Percentage of the selected
+// agents. A percentage of 0 or 100 makes the version live for every agent.
+type PromoteRolloutRequest struct {
+	Percentage int `json:"percentage"`
+}
+
+func (r PromoteRolloutRequest) Validate() error {
+	if r.Percentage < 0 || r.Percentage > 100 {
+		return errors.New("percentage must be between 0 and 100")
 	}
 	return nil
 }
+
+type PaginationRequest struct {
+	Page     int
+	PageSize int
+}
+
+func (r PaginationRequest) Validate() error {
+	if r.Page <= 0 {
+		return errors.New("page must be greater than 0")
+	}
+	if r.PageSize <= 0 || r.PageSize > MaxPageSize {
+		return errors.New("page_size must be between 1 and 100")
+	}
+	return nil
+}
+
+func (r PaginationRequest) Offset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+type ConfigDiffRequest struct {
+	From int64
+	To   int64
+}
+
+func (r ConfigDiffRequest) Validate() error {
+	if r.From <= 0 {
+		return errors.New("from must be greater than 0")
+	}
+	if r.To <= 0 {
+		return errors.New("to must be greater than 0")
+	}
+	return nil
+}
+
+// ConfigProposalFilter narrows proposals down by status; an empty status
+// does not filter.
+type ConfigProposalFilter struct {
+	Status string
+}
+
+func (r ConfigProposalFilter) Validate() error {
+	switch r.Status {
+	case "", "pending", "approved", "expired":
+		return nil
+	}
+	return errors.New("status must be pending, approved or expired")
+}
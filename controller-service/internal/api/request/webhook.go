@@ -0,0 +1,46 @@
+package request
+
+import (
+	"errors"
+	"net/url"
+)
+
+// WebhookRequest creates a webhook, or replaces its settings. An empty
+// Namespaces subscribes to every namespace; Enabled defaults to true.
+type WebhookRequest struct {
+	URL        string   `json:"url"`
+	Namespaces []string `json:"namespaces"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+func (r WebhookRequest) Validate() error {
+	target, err := url.Parse(r.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	for _, namespace := range r.Namespaces {
+		if err := ValidateNamespace(namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsEnabled reports whether the webhook should receive events.
+func (r WebhookRequest) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// WebhookDeliveryFilter narrows deliveries down by status; an empty status
+// does not filter.
+type WebhookDeliveryFilter struct {
+	Status string
+}
+
+func (r WebhookDeliveryFilter) Validate() error {
+	switch r.Status {
+	case "", "pending", "delivered", "failed":
+		return nil
+	}
+	return errors.New("status must be pending, delivered or failed")
+}
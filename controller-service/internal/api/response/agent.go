@@ -0,0 +1,45 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AgentStatusHealthy   = "healthy"
+	AgentStatusUnhealthy = "unhealthy"
+	AgentStatusStale     = "stale"
+	AgentStatusOffline   = "offline"
+)
+
+type AgentResponse struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Namespace      string            `json:"namespace"`
+	Labels         map[string]string `json:"labels"`
+	Status         string            `json:"status"`
+	AppliedVersion *int64            `json:"applied_version"`
+	AppliedAt      *time.Time        `json:"applied_at"`
+	WorkerHealthy  *bool             `json:"worker_healthy"`
+	BuildInfo      map[string]string `json:"build_info"`
+	LastSeenAt     *time.Time        `json:"last_seen_at"`
+	DeregisteredAt *time.Time        `json:"deregistered_at"`
+	RegisteredBy   string            `json:"registered_by"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+type AgentListResponse struct {
+	Items             []AgentResponse `json:"items"`
+	StaleAfterSeconds int             `json:"stale_after_seconds"`
+}
+
+type AgentConfigOverrideResponse struct {
+	AgentID   string          `json:"agent_id"`
+	Config    json.RawMessage `json:"config" swaggertype:"object"`
+	Version   int64           `json:"version"`
+	UpdatedAt time.Time       `json:"updated_at"`
+
+	// Proposal is set when the agent's namespace requires approval; the
+	// override is then the current one.
+	Proposal *ConfigProposalResponse `json:"proposal,omitempty"`
+}
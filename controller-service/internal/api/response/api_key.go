@@ -0,0 +1,27 @@
+package response
+
+import "time"
+
+type APIKeyResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	KeyPrefix string     `json:"key_prefix"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	// CreatedBy is the actor that created the key, empty for keys created
+	// before it was recorded.
+	CreatedBy string `json:"created_by"`
+}
+
+// CreatedAPIKeyResponse carries the plaintext key, which is returned once
+// at creation and never stored.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type APIKeyListResponse struct {
+	Items []APIKeyResponse `json:"items"`
+}
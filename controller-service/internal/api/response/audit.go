@@ -0,0 +1,49 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionConfigUpdate        = "config.update"
+	AuditActionConfigRollback      = "config.rollback"
+	AuditActionConfigPromote       = "config.promote"
+	AuditActionConfigAbort         = "config.abort"
+	AuditActionConfigSchedule      = "config.schedule"
+	AuditActionConfigCancel        = "config.schedule.cancel"
+	AuditActionConfigActivate      = "config.activate"
+	AuditActionConfigPropose       = "config.propose"
+	AuditActionConfigApprove       = "config.proposal.approve"
+	AuditActionConfigExpire        = "config.proposal.expire"
+	AuditActionConfigSchemaSet     = "config.schema.set"
+	AuditActionAgentRegister       = "agent.register"
+	AuditActionAgentDeregister     = "agent.deregister"
+	AuditActionAgentDelete         = "agent.delete"
+	AuditActionAgentOverrideSet    = "agent.override.set"
+	AuditActionAgentOverrideDelete = "agent.override.delete"
+	AuditActionAPIKeyCreate        = "api_key.create"
+	AuditActionAPIKeyRevoke        = "api_key.revoke"
+	AuditActionWebhookCreate       = "webhook.create"
+	AuditActionWebhookUpdate       = "webhook.update"
+	AuditActionWebhookDelete       = "webhook.delete"
+)
+
+type AuditEventResponse struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Resource  string          `json:"resource"`
+	SourceIP  string          `json:"source_ip"`
+	RequestID string          `json:"request_id"`
+	Before    json.RawMessage `json:"before" swaggertype:"object"`
+	After     json.RawMessage `json:"after" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AuditEventListResponse struct {
+	Items    []AuditEventResponse `json:"items"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+	Total    int64                `json:"total"`
+}
@@ -0,0 +1,44 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ConfigImportUnchanged = "unchanged"
+	ConfigImportUpdated   = "updated"
+	ConfigImportProposed  = "proposed"
+)
+
+// ConfigBundle holds every namespace of the controller. Config is the
+// document of the latest active version, what importing the bundle applies.
+type ConfigBundle struct {
+	ExportedAt time.Time               `json:"exported_at"`
+	Namespaces []ConfigBundleNamespace `json:"namespaces"`
+}
+
+type ConfigBundleNamespace struct {
+	Namespace string                  `json:"namespace"`
+	Schema    json.RawMessage         `json:"schema,omitempty" swaggertype:"object"`
+	Config    json.RawMessage         `json:"config,omitempty" swaggertype:"object"`
+	Version   int64                   `json:"version,omitempty"`
+	Versions  []ConfigVersionResponse `json:"versions"`
+}
+
+// ConfigImportResult tells what importing a namespace changed. Config and
+// Schema are empty when the bundle left them out; Version is the version of
+// the namespace after the import.
+type ConfigImportResult struct {
+	Namespace  string `json:"namespace"`
+	Config     string `json:"config,omitempty"`
+	Schema     string `json:"schema,omitempty"`
+	Version    int64  `json:"version,omitempty"`
+	ProposalID string `json:"proposal_id,omitempty"`
+	// SchemaProposalID is set when Schema is proposed.
+	SchemaProposalID string `json:"schema_proposal_id,omitempty"`
+}
+
+type ConfigImportResponse struct {
+	Items []ConfigImportResult `json:"items"`
+}
@@ -1,7 +1,159 @@
 package response
 
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
 type ConfigResponse struct {
-	AgentID      string `json:"agent_id,omitempty"`
-	PollURL      string `json:"poll_url"`
-	PollInterval int    `json:"poll_interval"`
+	AgentID         string          `json:"agent_id,omitempty"`
+	Namespace       string          `json:"namespace"`
+	PollURL         string          `json:"poll_url"`
+	PollInterval    int             `json:"poll_interval"`
+	Config          json.RawMessage `json:"config" swaggertype:"object"`
+	GlobalVersion   int64           `json:"global_version,omitempty"`
+	OverrideVersion int64           `json:"override_version,omitempty"`
+
+	// Signature is the base64 Ed25519 signature of the Version header and
+	// Config, set when the controller has a signing key.
+	Signature string `json:"signature,omitempty"`
+}
+
+// ETag is the entity tag of a config version, as sent in the ETag header
+// and accepted in If-Match.
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+type ConfigUpdateResponse struct {
+	Namespace string `json:"namespace"`
+	Version   int64  `json:"version"`
+	Status    string `json:"status"`
+	ETag      string `json:"etag"`
+
+	// ScheduledID and EffectiveAt are set when the version was scheduled
+	// rather than committed; ETag then still names the current version.
+	ScheduledID string     `json:"scheduled_id,omitempty"`
+	EffectiveAt *time.Time `json:"effective_at,omitempty"`
+
+	// Proposal is set when the namespace requires approval; nothing is
+	// written until another key holder approves it.
+	Proposal *ConfigProposalResponse `json:"proposal,omitempty"`
+
+	// Override is the agent config override an approved override proposal
+	// set.
+	Override *AgentConfigOverrideResponse `json:"override,omitempty"`
+
+	// Schema is the schema an approved schema proposal set, Cancelled the
+	// scheduled config an approved cancel proposal deleted.
+	Schema    *ConfigSchemaResponse    `json:"schema,omitempty"`
+	Cancelled *ScheduledConfigResponse `json:"cancelled,omitempty"`
+}
+
+type ConfigConflictResponse struct {
+	Error          string `json:"error"`
+	CurrentVersion int64  `json:"current_version"`
+	ETag           string `json:"etag"`
+}
+
+type ConfigSchemaResponse struct {
+	Namespace string          `json:"namespace"`
+	Schema    json.RawMessage `json:"schema" swaggertype:"object"`
+	UpdatedAt time.Time       `json:"updated_at"`
+
+	// Proposal is set when the namespace requires approval; the schema is
+	// then the current one, if any.
+	Proposal *ConfigProposalResponse `json:"proposal,omitempty"`
+}
+
+const (
+	ConfigStatusActive  = "active"
+	ConfigStatusCanary  = "canary"
+	ConfigStatusAborted = "aborted"
+	// ConfigStatusScheduled is the status of an update waiting for its
+	// effective_at; it takes a version only once activated.
+	ConfigStatusScheduled = "scheduled"
+)
+
+type ConfigVersionResponse struct {
+	Namespace string          `json:"namespace"`
+	Version   int64           `json:"version"`
+	Status    string          `json:"status"`
+	Rollout   *RolloutPolicy  `json:"rollout,omitempty"`
+	Config    json.RawMessage `json:"config" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at"`
+	// EffectiveAt is set on versions activated from a schedule, to the time
+	// they were scheduled for.
+	EffectiveAt *time.Time `json:"effective_at,omitempty"`
+
+	// Proposal is set when a rollback, promotion or abort waits for
+	// approval; the version is then the current one, or the promoted or
+	// aborted one as it is.
+	Proposal *ConfigProposalResponse `json:"proposal,omitempty"`
+}
+
+// ScheduledConfigResponse is an update waiting for its effective_at. It has
+// no version until activated, when it takes the next one of its namespace.
+type ScheduledConfigResponse struct {
+	ID          string          `json:"id"`
+	Namespace   string          `json:"namespace"`
+	Status      string          `json:"status"`
+	Rollout     *RolloutPolicy  `json:"rollout,omitempty"`
+	Config      json.RawMessage `json:"config" swaggertype:"object"`
+	EffectiveAt time.Time       `json:"effective_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+
+	// Proposal is set when a cancellation waits for approval; the config
+	// stays scheduled until then.
+	Proposal *ConfigProposalResponse `json:"proposal,omitempty"`
+}
+
+type ScheduledConfigListResponse struct {
+	Items []ScheduledConfigResponse `json:"items"`
+}
+
+type RolloutPolicy struct {
+	Percentage int               `json:"percentage"`
+	Selector   map[string]string `json:"selector,omitempty"`
+}
+
+type ConfigVersionListResponse struct {
+	Items    []ConfigVersionResponse `json:"items"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"page_size"`
+	Total    int64                   `json:"total"`
+}
+
+type ConfigFieldChange struct {
+	Field string `json:"field"`
+	Type  string `json:"type"`
+	From  any    `json:"from,omitempty"`
+	To    any    `json:"to,omitempty"`
+}
+
+type ConfigDiffResponse struct {
+	From    int64               `json:"from"`
+	To      int64               `json:"to"`
+	Changes []ConfigFieldChange `json:"changes"`
+}
+
+type RolloutAgent struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Status         string     `json:"status"`
+	AppliedVersion *int64     `json:"applied_version"`
+	LastSeenAt     *time.Time `json:"last_seen_at"`
+}
+
+type ConfigRolloutResponse struct {
+	Namespace          string         `json:"namespace"`
+	Version            int64          `json:"version"`
+	Status             string         `json:"status"`
+	CreatedAt          time.Time      `json:"created_at"`
+	TotalAgents        int            `json:"total_agents"`
+	AcknowledgedAgents int            `json:"acknowledged_agents"`
+	Complete           bool           `json:"complete"`
+	PropagationSeconds *float64       `json:"propagation_seconds"`
+	LaggingAgents      []RolloutAgent `json:"lagging_agents"`
 }
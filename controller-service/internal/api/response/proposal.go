@@ -0,0 +1,62 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// ConfigStatusProposed is the status of an update that was stored as a
+	// proposal instead of a version.
+	ConfigStatusProposed = "proposed"
+
+	ConfigProposalStatusPending  = "pending"
+	ConfigProposalStatusApproved = "approved"
+	ConfigProposalStatusExpired  = "expired"
+
+	// ConfigProposalKindUpdate proposals are POST /config bodies,
+	// ConfigProposalKindRollback rollbacks to RollbackVersion,
+	// ConfigProposalKindOverride config overrides of AgentID,
+	// ConfigProposalKindSchema schema replacements, ConfigProposalKindPromote
+	// and ConfigProposalKindAbort rollout changes of TargetVersion and
+	// ConfigProposalKindCancel cancellations of ScheduledID.
+	ConfigProposalKindUpdate   = "update"
+	ConfigProposalKindRollback = "rollback"
+	ConfigProposalKindOverride = "override"
+	ConfigProposalKindSchema   = "schema"
+	ConfigProposalKindPromote  = "promote"
+	ConfigProposalKindAbort    = "abort"
+	ConfigProposalKindCancel   = "cancel"
+)
+
+// ConfigProposalResponse is a config change waiting for approval. Config,
+// Rollout, ExpectedVersion and EffectiveAt are the update as proposed; for a
+// rollback Config is the document of RollbackVersion, for an override the
+// override of AgentID, for a schema proposal the schema, for a promotion or
+// abort the document of TargetVersion and for a cancellation the scheduled
+// document. Version is the version its approval created, the override
+// version for overrides and TargetVersion for promotions and aborts.
+type ConfigProposalResponse struct {
+	ID              string          `json:"id"`
+	Namespace       string          `json:"namespace"`
+	Kind            string          `json:"kind"`
+	Status          string          `json:"status"`
+	Config          json.RawMessage `json:"config" swaggertype:"object"`
+	RollbackVersion int64           `json:"rollback_version,omitempty"`
+	AgentID         string          `json:"agent_id,omitempty"`
+	TargetVersion   int64           `json:"target_version,omitempty"`
+	ScheduledID     string          `json:"scheduled_id,omitempty"`
+	Rollout         *RolloutPolicy  `json:"rollout,omitempty"`
+	ExpectedVersion *int64          `json:"expected_version,omitempty"`
+	EffectiveAt     *time.Time      `json:"effective_at,omitempty"`
+	ProposedBy      string          `json:"proposed_by"`
+	ApprovedBy      string          `json:"approved_by,omitempty"`
+	Version         int64           `json:"version,omitempty"`
+	ExpiresAt       time.Time       `json:"expires_at"`
+	DecidedAt       *time.Time      `json:"decided_at,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+type ConfigProposalListResponse struct {
+	Items []ConfigProposalResponse `json:"items"`
+}
@@ -0,0 +1,68 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookDeliveryStatusPending   = "pending"
+	WebhookDeliveryStatusDelivered = "delivered"
+	WebhookDeliveryStatusFailed    = "failed"
+)
+
+type WebhookResponse struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	Namespaces []string  `json:"namespaces"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CreatedWebhookResponse carries the secret deliveries are signed with,
+// which is only returned at creation.
+type CreatedWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhookListResponse struct {
+	Items []WebhookResponse `json:"items"`
+}
+
+// WebhookEvent is the JSON body delivered to webhooks. Type is the audit
+// action of the change, Diff the changes from PreviousVersion to Version.
+type WebhookEvent struct {
+	ID              string              `json:"id"`
+	Type            string              `json:"type"`
+	Namespace       string              `json:"namespace"`
+	Version         int64               `json:"version"`
+	PreviousVersion int64               `json:"previous_version"`
+	Status          string              `json:"status"`
+	Actor           string              `json:"actor"`
+	Diff            []ConfigFieldChange `json:"diff"`
+	CreatedAt       time.Time           `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastStatusCode *int32          `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Items    []WebhookDeliveryResponse `json:"items"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"page_size"`
+	Total    int64                     `json:"total"`
+}
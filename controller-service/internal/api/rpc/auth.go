@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"context"
+	"controller-service/internal/api/middleware"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/rpc/controllerpb"
+	"controller-service/internal/requestctx"
+	"controller-service/internal/tlsutil"
+	"errors"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodScopes holds the scope each RPC requires, matching its REST route.
+var methodScopes = map[string]string{
+	controllerpb.ControllerService_Register_FullMethodName:     request.ScopeAgentsRegister,
+	controllerpb.ControllerService_GetConfig_FullMethodName:    request.ScopeConfigRead,
+	controllerpb.ControllerService_UpdateConfig_FullMethodName: request.ScopeConfigWrite,
+	controllerpb.ControllerService_WatchConfig_FullMethodName:  request.ScopeConfigRead,
+	controllerpb.ControllerService_Heartbeat_FullMethodName:    request.ScopeAgentsRegister,
+	controllerpb.ControllerService_Deregister_FullMethodName:   request.ScopeAgentsRegister,
+}
+
+func (s *ControllerServer) authenticateUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *ControllerServer) authenticateStream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticate checks the x-api-key metadata against the scope of the
+// method and returns ctx with the request info the REST middleware sets.
+func (s *ControllerServer) authenticate(ctx context.Context, method string) (context.Context, error) {
+	scope, ok := methodScopes[method]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "forbidden")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	name, scopes, err := middleware.Authenticate(ctx, s.BootstrapKey, s.Keys, firstValue(md, "x-api-key"))
+	if errors.Is(err, middleware.ErrUnauthorized) {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to authenticate")
+	}
+
+	if !request.HasScope(scopes, scope) {
+		return nil, status.Error(codes.PermissionDenied, "forbidden: requires scope "+scope)
+	}
+
+	var sourceIP, certificate string
+	if p, ok := peer.FromContext(ctx); ok {
+		sourceIP, _, err = net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			sourceIP = p.Addr.String()
+		}
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			certificate = tlsutil.CertificateIdentity(tlsInfo.State.VerifiedChains[0][0])
+		}
+	}
+
+	return requestctx.WithInfo(ctx, requestctx.Info{
+		Actor:       "api-key:" + name,
+		SourceIP:    sourceIP,
+		RequestID:   middleware.RequestID(firstValue(md, "x-request-id")),
+		Certificate: certificate,
+	}), nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream is a server stream whose handlers see the authenticated
+// context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,302 @@
+// Package rpc serves ControllerService, the gRPC API of the controller, over
+// the same service layer as the REST handlers.
+package rpc
+
+//go:generate protoc --proto_path=../../../proto --go_out=../../.. --go_opt=module=controller-service --go-grpc_out=../../.. --go-grpc_opt=module=controller-service controller/v1/controller.proto
+
+import (
+	"context"
+	"controller-service/internal/api/middleware"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	"controller-service/internal/api/rpc/controllerpb"
+	"controller-service/internal/service"
+	"controller-service/internal/tlsutil"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ControllerServer struct {
+	controllerpb.UnimplementedControllerServiceServer
+
+	Service service.IControllerService
+	// BootstrapKey and Keys check the x-api-key metadata like the REST API
+	// checks X-API-Key.
+	BootstrapKey string
+	Keys         middleware.APIKeyAuthenticator
+}
+
+// NewGRPCServer returns a gRPC server serving s over TLS. tlsConfig must not
+// be shared with the HTTPS server, as gRPC needs its own ALPN protocol.
+func (s *ControllerServer) NewGRPCServer(tlsConfig *tls.Config) *grpc.Server {
+	// set on the config itself, as tlsutil hands out copies of it per
+	// handshake
+	tlsConfig.NextProtos = []string{"h2"}
+
+	server := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.UnaryInterceptor(s.authenticateUnary),
+		grpc.StreamInterceptor(s.authenticateStream),
+	)
+	controllerpb.RegisterControllerServiceServer(server, s)
+	return server
+}
+
+func (s *ControllerServer) Register(ctx context.Context, req *controllerpb.RegisterRequest) (*controllerpb.ConfigResponse, error) {
+	payload := request.RegisterAgentRequest{
+		Name:      req.GetName(),
+		Namespace: req.GetNamespace(),
+		Labels:    req.GetLabels(),
+	}
+	if err := payload.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := agentNameFromPeer(ctx, &payload); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	var (
+		config *response.ConfigResponse
+		err    error
+	)
+	if req.GetAgentId() == "" {
+		config, err = s.Service.RegisterAgent(ctx, payload)
+	} else {
+		agentID, parseErr := uuid.Parse(req.GetAgentId())
+		if parseErr != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid agent id")
+		}
+		config, err = s.Service.ReregisterAgent(ctx, agentID, payload)
+	}
+	if errors.Is(err, service.ErrAgentForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to register agent")
+	}
+
+	return toConfigResponse(config, 0), nil
+}
+
+func (s *ControllerServer) GetConfig(ctx context.Context, req *controllerpb.GetConfigRequest) (*controllerpb.ConfigResponse, error) {
+	if err := validateNamespace(req.GetNamespace()); err != nil {
+		return nil, err
+	}
+
+	config, version, err := s.Service.GetConfig(ctx, req.GetNamespace(), req.GetAgentId())
+	if errors.Is(err, service.ErrInvalidAgentID) {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent id")
+	}
+	if errors.Is(err, service.ErrAgentForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.Is(err, service.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "config not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get config")
+	}
+
+	return toConfigResponse(config, version), nil
+}
+
+func (s *ControllerServer) UpdateConfig(ctx context.Context, req *controllerpb.UpdateConfigRequest) (*controllerpb.UpdateConfigResponse, error) {
+	var payload request.UpdateConfigRequest
+	if err := json.Unmarshal(req.GetDocument(), &payload); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid config document")
+	}
+
+	if req.ExpectedVersion != nil {
+		if payload.ExpectedVersion != nil && *payload.ExpectedVersion != req.GetExpectedVersion() {
+			return nil, status.Error(codes.InvalidArgument, "expected_version of the request and the document disagree")
+		}
+		payload.ExpectedVersion = req.ExpectedVersion
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	namespace := req.GetNamespace()
+	if namespace == "" {
+		namespace = request.DefaultNamespace
+	}
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	config, err := s.Service.UpdateConfig(ctx, namespace, payload)
+	if errors.Is(err, service.ErrInvalidConfig) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var conflictErr *service.VersionConflictError
+	if errors.As(err, &conflictErr) {
+		return nil, status.Error(codes.Aborted, fmt.Sprintf("%s: current version is %d", conflictErr.Error(), conflictErr.CurrentVersion))
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to update config")
+	}
+
+	return toUpdateConfigResponse(config), nil
+}
+
+// WatchConfig sends the served config whenever its version differs from the
+// last one sent, until the client goes away.
+func (s *ControllerServer) WatchConfig(req *controllerpb.WatchConfigRequest, stream grpc.ServerStreamingServer[controllerpb.ConfigResponse]) error {
+	if err := validateNamespace(req.GetNamespace()); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	sinceVersion := int(req.GetSinceVersion())
+	for {
+		config, version, err := s.Service.WatchConfig(ctx, req.GetNamespace(), req.GetAgentId(), sinceVersion)
+		if errors.Is(err, service.ErrNotModified) {
+			if ctx.Err() == nil {
+				// the server is shutting down, the client reconnects
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if errors.Is(err, service.ErrInvalidAgentID) {
+			return status.Error(codes.InvalidArgument, "invalid agent id")
+		}
+		if errors.Is(err, service.ErrAgentForbidden) {
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+		if err != nil {
+			return status.Error(codes.Internal, "failed to watch config")
+		}
+
+		if err := stream.Send(toConfigResponse(config, version)); err != nil {
+			return err
+		}
+		sinceVersion = version
+	}
+}
+
+func (s *ControllerServer) Heartbeat(ctx context.Context, req *controllerpb.HeartbeatRequest) (*controllerpb.HeartbeatResponse, error) {
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent id")
+	}
+
+	payload := request.AgentHeartbeatRequest{
+		AppliedVersion: req.GetAppliedVersion(),
+		WorkerHealthy:  req.GetWorkerHealthy(),
+		BuildInfo:      req.GetBuildInfo(),
+	}
+	if err := payload.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.Service.RecordHeartbeat(ctx, agentID, payload)
+	if errors.Is(err, service.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "agent not found")
+	}
+	if errors.Is(err, service.ErrAgentForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to record heartbeat")
+	}
+
+	return &controllerpb.HeartbeatResponse{}, nil
+}
+
+func (s *ControllerServer) Deregister(ctx context.Context, req *controllerpb.DeregisterRequest) (*controllerpb.DeregisterResponse, error) {
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent id")
+	}
+
+	err = s.Service.DeregisterAgent(ctx, agentID)
+	if errors.Is(err, service.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "agent not found")
+	}
+	if errors.Is(err, service.ErrAgentForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to deregister agent")
+	}
+
+	return &controllerpb.DeregisterResponse{}, nil
+}
+
+func toConfigResponse(config *response.ConfigResponse, version int) *controllerpb.ConfigResponse {
+	return &controllerpb.ConfigResponse{
+		AgentId:         config.AgentID,
+		Namespace:       config.Namespace,
+		PollUrl:         config.PollURL,
+		PollInterval:    int32(config.PollInterval),
+		Config:          config.Config,
+		GlobalVersion:   config.GlobalVersion,
+		OverrideVersion: config.OverrideVersion,
+		Version:         int64(version),
+		Signature:       config.Signature,
+	}
+}
+
+// validateNamespace accepts an empty namespace, which the service resolves
+// like the REST routes without /namespaces/{ns}.
+func validateNamespace(namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	if err := request.ValidateNamespace(namespace); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// agentNameFromPeer binds the agent name to the verified client certificate
+// of the connection, like the REST handlers do.
+func agentNameFromPeer(ctx context.Context, payload *request.RegisterAgentRequest) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+		return nil
+	}
+	return payload.BindCertificateIdentity(tlsutil.CertificateIdentity(tlsInfo.State.VerifiedChains[0][0]))
+}
+
+func toUpdateConfigResponse(config *response.ConfigUpdateResponse) *controllerpb.UpdateConfigResponse {
+	resp := &controllerpb.UpdateConfigResponse{
+		Namespace:   config.Namespace,
+		Version:     config.Version,
+		Status:      config.Status,
+		Etag:        config.ETag,
+		ScheduledId: config.ScheduledID,
+	}
+	if config.EffectiveAt != nil {
+		resp.EffectiveAt = timestamppb.New(*config.EffectiveAt)
+	}
+	if config.Proposal != nil {
+		resp.Proposal = &controllerpb.ConfigProposal{
+			Id:         config.Proposal.ID,
+			Namespace:  config.Proposal.Namespace,
+			Kind:       config.Proposal.Kind,
+			Status:     config.Proposal.Status,
+			ProposedBy: config.Proposal.ProposedBy,
+			ExpiresAt:  timestamppb.New(config.Proposal.ExpiresAt),
+			CreatedAt:  timestamppb.New(config.Proposal.CreatedAt),
+		}
+	}
+	return resp
+}
@@ -4,15 +4,45 @@ import (
 	"log/slog"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
 	AppPort     string
+	GRPCPort    string
 	DBURL       string
 	APIKey      string
 	PollSeconds int
 	TLSCertFile string
 	TLSKeyFile  string
+
+	// TLSClientCAFile enables client certificate verification; TLSClientAuth
+	// is "require" (default) or "optional".
+	TLSClientCAFile string
+	TLSClientAuth   string
+
+	// ConfigSigningKeyFile (PEM) or ConfigSigningKey (base64 seed) holds the
+	// Ed25519 key served configs are signed with
+	ConfigSigningKeyFile string
+	ConfigSigningKey     string
+
+	WatchTimeoutSeconds    int
+	StreamKeepAliveSeconds int
+	AgentStaleSeconds      int
+	AgentReapAfterDays     int
+
+	// ApprovalRequiredNamespaces need a second key holder to approve config
+	// updates, which wait ProposalTTLHours for it.
+	ApprovalRequiredNamespaces []string
+	ProposalTTLHours           int
+
+	// ConfigSyncDir, when set, holds a <namespace>.json or .yaml document
+	// per namespace, imported whenever it changes
+	ConfigSyncDir string
+
+	// ShutdownTimeoutSeconds bounds how long in-flight requests may drain
+	// on SIGTERM; keep it below the termination grace period
+	ShutdownTimeoutSeconds int
 }
 
 func Load() Config {
@@ -30,17 +60,108 @@ func Load() Config {
 		}
 	}
 
+	watchTimeoutSeconds := 25
+	watchTimeoutEnv := os.Getenv("WATCH_TIMEOUT_SECONDS")
+	if watchTimeoutEnv != "" {
+		watchTimeoutSeconds, err = strconv.Atoi(watchTimeoutEnv)
+		if err != nil || watchTimeoutSeconds <= 0 {
+			slog.Info("Invalid WATCH_TIMEOUT_SECONDS value, using default of 25 seconds", slog.String("WATCH_TIMEOUT_SECONDS", watchTimeoutEnv), slog.Any("error", err))
+			watchTimeoutSeconds = 25 // default value if conversion fails
+		}
+	}
+
+	streamKeepAliveSeconds := 15
+	streamKeepAliveEnv := os.Getenv("STREAM_KEEPALIVE_SECONDS")
+	if streamKeepAliveEnv != "" {
+		streamKeepAliveSeconds, err = strconv.Atoi(streamKeepAliveEnv)
+		if err != nil || streamKeepAliveSeconds <= 0 {
+			slog.Info("Invalid STREAM_KEEPALIVE_SECONDS value, using default of 15 seconds", slog.String("STREAM_KEEPALIVE_SECONDS", streamKeepAliveEnv), slog.Any("error", err))
+			streamKeepAliveSeconds = 15 // default value if conversion fails
+		}
+	}
+
+	agentStaleSeconds := 90
+	agentStaleEnv := os.Getenv("AGENT_STALE_SECONDS")
+	if agentStaleEnv != "" {
+		agentStaleSeconds, err = strconv.Atoi(agentStaleEnv)
+		if err != nil || agentStaleSeconds <= 0 {
+			slog.Info("Invalid AGENT_STALE_SECONDS value, using default of 90 seconds", slog.String("AGENT_STALE_SECONDS", agentStaleEnv), slog.Any("error", err))
+			agentStaleSeconds = 90 // default value if conversion fails
+		}
+	}
+
+	var agentReapAfterDays int
+	agentReapEnv := os.Getenv("AGENT_REAP_AFTER_DAYS")
+	if agentReapEnv != "" {
+		agentReapAfterDays, err = strconv.Atoi(agentReapEnv)
+		if err != nil || agentReapAfterDays < 0 {
+			slog.Info("Invalid AGENT_REAP_AFTER_DAYS value, agent reaper disabled", slog.String("AGENT_REAP_AFTER_DAYS", agentReapEnv), slog.Any("error", err))
+			agentReapAfterDays = 0 // disabled if conversion fails
+		}
+	}
+
+	proposalTTLHours := 24
+	proposalTTLEnv := os.Getenv("PROPOSAL_TTL_HOURS")
+	if proposalTTLEnv != "" {
+		proposalTTLHours, err = strconv.Atoi(proposalTTLEnv)
+		if err != nil || proposalTTLHours <= 0 {
+			slog.Info("Invalid PROPOSAL_TTL_HOURS value, using default of 24 hours", slog.String("PROPOSAL_TTL_HOURS", proposalTTLEnv), slog.Any("error", err))
+			proposalTTLHours = 24 // default value if conversion fails
+		}
+	}
+
+	shutdownTimeoutSeconds := 25
+	shutdownTimeoutEnv := os.Getenv("SHUTDOWN_TIMEOUT_SECONDS")
+	if shutdownTimeoutEnv != "" {
+		shutdownTimeoutSeconds, err = strconv.Atoi(shutdownTimeoutEnv)
+		if err != nil || shutdownTimeoutSeconds <= 0 {
+			slog.Info("Invalid SHUTDOWN_TIMEOUT_SECONDS value, using default of 25 seconds", slog.String("SHUTDOWN_TIMEOUT_SECONDS", shutdownTimeoutEnv), slog.Any("error", err))
+			shutdownTimeoutSeconds = 25 // default value if conversion fails
+		}
+	}
+
+	var approvalRequiredNamespaces []string
+	for _, namespace := range strings.Split(os.Getenv("APPROVAL_REQUIRED_NAMESPACES"), ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			approvalRequiredNamespaces = append(approvalRequiredNamespaces, namespace)
+		}
+	}
+
 	appPort := os.Getenv("APP_PORT")
 	if appPort == "" {
 		appPort = "8080"
 	}
 
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+
 	return Config{
 		AppPort:     appPort,
+		GRPCPort:    grpcPort,
 		DBURL:       os.Getenv("DB_URL"),
 		APIKey:      os.Getenv("API_KEY"),
 		PollSeconds: pollSeconds,
 		TLSCertFile: os.Getenv("TLS_CERT_FILE"),
 		TLSKeyFile:  os.Getenv("TLS_KEY_FILE"),
+
+		TLSClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+		TLSClientAuth:   os.Getenv("TLS_CLIENT_AUTH"),
+
+		ConfigSigningKeyFile: os.Getenv("CONFIG_SIGNING_KEY_FILE"),
+		ConfigSigningKey:     os.Getenv("CONFIG_SIGNING_KEY"),
+
+		WatchTimeoutSeconds:    watchTimeoutSeconds,
+		StreamKeepAliveSeconds: streamKeepAliveSeconds,
+		AgentStaleSeconds:      agentStaleSeconds,
+		AgentReapAfterDays:     agentReapAfterDays,
+
+		ApprovalRequiredNamespaces: approvalRequiredNamespaces,
+		ProposalTTLHours:           proposalTTLHours,
+
+		ConfigSyncDir: os.Getenv("CONFIG_SYNC_DIR"),
+
+		ShutdownTimeoutSeconds: shutdownTimeoutSeconds,
 	}
 }
@@ -0,0 +1,5 @@
+ALTER TABLE agents
+    DROP COLUMN IF EXISTS build_info,
+    DROP COLUMN IF EXISTS worker_healthy,
+    DROP COLUMN IF EXISTS applied_version,
+    DROP COLUMN IF EXISTS last_seen_at;
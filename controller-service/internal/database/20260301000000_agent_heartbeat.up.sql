@@ -0,0 +1,5 @@
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS applied_version BIGINT,
+    ADD COLUMN IF NOT EXISTS worker_healthy BOOLEAN,
+    ADD COLUMN IF NOT EXISTS build_info JSONB NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,2 @@
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS applied_at TIMESTAMP;
@@ -0,0 +1,7 @@
+ALTER TABLE agents
+    DROP COLUMN IF EXISTS namespace;
+
+DROP INDEX IF EXISTS idx_global_config_namespace_version;
+
+ALTER TABLE global_config
+    DROP COLUMN IF EXISTS namespace;
@@ -0,0 +1,7 @@
+ALTER TABLE global_config
+    ADD COLUMN IF NOT EXISTS namespace TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_global_config_namespace_version ON global_config (namespace, version DESC);
+
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS namespace TEXT NOT NULL DEFAULT 'default';
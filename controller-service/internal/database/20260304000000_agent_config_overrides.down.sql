@@ -0,0 +1,11 @@
+ALTER TABLE agents
+    DROP COLUMN IF EXISTS config_revision;
+
+DROP INDEX IF EXISTS idx_global_config_namespace_revision;
+
+ALTER TABLE global_config
+    DROP COLUMN IF EXISTS revision;
+
+DROP SEQUENCE IF EXISTS config_revision_seq;
+
+DROP TABLE IF EXISTS agent_config_overrides;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS agent_config_overrides (
+    agent_id UUID PRIMARY KEY REFERENCES agents (id) ON DELETE CASCADE,
+    config JSONB NOT NULL,
+    version BIGINT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- served config versions are revisions from one sequence; they start past
+-- the namespace versions served so far, so agents never see theirs go back
+CREATE SEQUENCE IF NOT EXISTS config_revision_seq;
+
+SELECT setval('config_revision_seq', (SELECT COALESCE(MAX(version), 0) + 1 FROM global_config));
+
+ALTER TABLE global_config
+    ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT nextval('config_revision_seq');
+
+CREATE INDEX IF NOT EXISTS idx_global_config_namespace_revision ON global_config (namespace, revision DESC);
+
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS config_revision BIGINT NOT NULL DEFAULT nextval('config_revision_seq');
@@ -0,0 +1,7 @@
+ALTER TABLE agents
+    DROP COLUMN IF EXISTS labels;
+
+ALTER TABLE global_config
+    DROP COLUMN IF EXISTS rollout_selector,
+    DROP COLUMN IF EXISTS rollout_percentage,
+    DROP COLUMN IF EXISTS status;
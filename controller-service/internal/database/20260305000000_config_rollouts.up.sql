@@ -0,0 +1,7 @@
+ALTER TABLE global_config
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS rollout_percentage INT NOT NULL DEFAULT 100,
+    ADD COLUMN IF NOT EXISTS rollout_selector JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE agents
+    ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS config_schemas (
+    namespace TEXT PRIMARY KEY,
+    schema JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_global_config_namespace_version;
+
+CREATE INDEX IF NOT EXISTS idx_global_config_namespace_version ON global_config (namespace, version DESC);
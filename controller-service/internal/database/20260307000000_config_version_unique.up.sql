@@ -0,0 +1,23 @@
+-- concurrent updates could create the same version twice; move the later
+-- copies past the highest version of their namespace before enforcing it
+WITH copies AS (
+    SELECT id, namespace, version, created_at,
+        ROW_NUMBER() OVER (PARTITION BY namespace, version ORDER BY created_at, id) AS copy
+    FROM global_config
+),
+renumbered AS (
+    SELECT c.id,
+        m.max_version + ROW_NUMBER() OVER (PARTITION BY c.namespace ORDER BY c.version, c.created_at, c.id) AS version
+    FROM copies c
+    JOIN (SELECT namespace, MAX(version) AS max_version FROM global_config GROUP BY namespace) m
+        ON m.namespace = c.namespace
+    WHERE c.copy > 1
+)
+UPDATE global_config g
+SET version = r.version
+FROM renumbered r
+WHERE g.id = r.id;
+
+DROP INDEX IF EXISTS idx_global_config_namespace_version;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_global_config_namespace_version ON global_config (namespace, version DESC);
@@ -0,0 +1,4 @@
+ALTER TABLE agents
+    DROP COLUMN IF EXISTS registered_by;
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS scheduled_configs;
+
+ALTER TABLE global_config
+    DROP COLUMN IF EXISTS effective_at;
@@ -0,0 +1,17 @@
+-- config updates waiting for their effective_at; they take a version in
+-- global_config only once activated, and effective_at is kept there
+CREATE TABLE IF NOT EXISTS scheduled_configs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    namespace TEXT NOT NULL,
+    config JSONB NOT NULL,
+    rollout_percentage INT NOT NULL DEFAULT 100,
+    rollout_selector JSONB NOT NULL DEFAULT '{}',
+    effective_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_configs_due ON scheduled_configs (effective_at);
+CREATE INDEX IF NOT EXISTS idx_scheduled_configs_namespace ON scheduled_configs (namespace, effective_at);
+
+ALTER TABLE global_config
+    ADD COLUMN IF NOT EXISTS effective_at TIMESTAMP;
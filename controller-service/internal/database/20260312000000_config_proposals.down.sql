@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS config_proposals;
+
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS created_by;
@@ -0,0 +1,25 @@
+-- config changes waiting for a second key holder's approval; kind says what
+-- request holds (the body of the change, replayed on approval), agent_id is
+-- the agent an override proposal is for
+CREATE TABLE IF NOT EXISTS config_proposals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    namespace TEXT NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'update',
+    agent_id UUID,
+    request JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    proposed_by TEXT NOT NULL,
+    approved_by TEXT NOT NULL DEFAULT '',
+    version BIGINT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_config_proposals_namespace ON config_proposals (namespace, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_config_proposals_pending ON config_proposals (expires_at) WHERE status = 'pending';
+
+-- who created each API key, so approvals can tell keys of the same holder
+-- apart from a second one; empty for keys created before
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '';
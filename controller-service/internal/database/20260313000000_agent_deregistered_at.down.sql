@@ -0,0 +1,2 @@
+ALTER TABLE agents
+    DROP COLUMN IF EXISTS deregistered_at;
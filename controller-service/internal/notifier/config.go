@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ConfigChannel is the Postgres channel notified whenever a new global config
+// version is committed.
+const ConfigChannel = "global_config_updated"
+
+type ConfigNotifier struct {
+	listener *pq.Listener
+
+	mu     sync.Mutex
+	waitCh chan struct{}
+}
+
+func NewConfigNotifier(dbURL string) (*ConfigNotifier, error) {
+	n := &ConfigNotifier{
+		waitCh: make(chan struct{}),
+	}
+
+	n.listener = pq.NewListener(dbURL, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Error("ConfigNotifier listener event", slog.Any("event", event), slog.Any("error", err))
+		}
+	})
+
+	if err := n.listener.Listen(ConfigChannel); err != nil {
+		n.listener.Close()
+		return nil, err
+	}
+
+	go n.run()
+
+	return n, nil
+}
+
+// Changed returns a channel that is closed on the next config change.
+// Callers should grab the channel before reading the current version so a
+// change committed in between is not missed.
+func (n *ConfigNotifier) Changed() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.waitCh
+}
+
+func (n *ConfigNotifier) Close() error {
+	return n.listener.Close()
+}
+
+func (n *ConfigNotifier) run() {
+	for {
+		select {
+		case notification, ok := <-n.listener.Notify:
+			if !ok {
+				return
+			}
+			// A nil notification is sent after a reconnect, when notifications
+			// may have been lost, so waiters are woken up to re-check as well.
+			if notification != nil {
+				slog.Info("ConfigNotifier received config change", slog.String("payload", notification.Extra))
+			}
+			n.broadcast()
+		case <-time.After(90 * time.Second):
+			go n.listener.Ping()
+		}
+	}
+}
+
+func (n *ConfigNotifier) broadcast() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	close(n.waitCh)
+	n.waitCh = make(chan struct{})
+}
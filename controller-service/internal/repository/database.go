@@ -4,6 +4,7 @@ import (
 	"context"
 	queries "controller-service/internal/repository/sqlc"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -15,8 +16,76 @@ type IRepository interface {
 
 	// Global Config
 	CreateGlobalConfig(ctx context.Context, arg queries.CreateGlobalConfigParams) (queries.GlobalConfig, error)
-	GetLatestVersionGlobalConfig(ctx context.Context) (queries.GlobalConfig, error)
+	GetLatestVersionGlobalConfig(ctx context.Context, namespace string) (queries.GlobalConfig, error)
+	GetLatestCanaryGlobalConfig(ctx context.Context, namespace string) (queries.GlobalConfig, error)
+	GetMaxVersionGlobalConfig(ctx context.Context, namespace string) (int64, error)
+	GetRevisionGlobalConfig(ctx context.Context, namespace string) (int64, error)
+	GetCurrentVersionGlobalConfig(ctx context.Context, namespace string) (int64, error)
+	GetGlobalConfigByVersion(ctx context.Context, arg queries.GetGlobalConfigByVersionParams) (queries.GlobalConfig, error)
+	ListGlobalConfigs(ctx context.Context, arg queries.ListGlobalConfigsParams) ([]queries.GlobalConfig, error)
+	ListAllGlobalConfigs(ctx context.Context, namespace string) ([]queries.GlobalConfig, error)
+	ListGlobalConfigNamespaces(ctx context.Context) ([]string, error)
+	CountGlobalConfigs(ctx context.Context, namespace string) (int64, error)
+	UpdateGlobalConfigRollout(ctx context.Context, arg queries.UpdateGlobalConfigRolloutParams) (int64, error)
+	AbortCanaryGlobalConfigs(ctx context.Context, namespace string) (int64, error)
+	NotifyGlobalConfigUpdated(ctx context.Context, payload string) error
+
+	// Scheduled Config
+	CreateScheduledConfig(ctx context.Context, arg queries.CreateScheduledConfigParams) (queries.ScheduledConfig, error)
+	ListScheduledConfigs(ctx context.Context, namespace string) ([]queries.ScheduledConfig, error)
+	GetScheduledConfig(ctx context.Context, arg queries.GetScheduledConfigParams) (queries.ScheduledConfig, error)
+	GetDueScheduledConfig(ctx context.Context, now time.Time) (queries.ScheduledConfig, error)
+	DeleteScheduledConfig(ctx context.Context, arg queries.DeleteScheduledConfigParams) (queries.ScheduledConfig, error)
 
 	// Agent
-	CreateAgent(ctx context.Context, name string) (uuid.UUID, error)
+	CreateAgent(ctx context.Context, arg queries.CreateAgentParams) (uuid.UUID, error)
+	UpsertAgent(ctx context.Context, arg queries.UpsertAgentParams) (uuid.UUID, error)
+	GetAgent(ctx context.Context, id uuid.UUID) (queries.Agent, error)
+	DeleteStaleAgents(ctx context.Context, maxIdleDays int32) ([]queries.Agent, error)
+	UpdateAgentHeartbeat(ctx context.Context, arg queries.UpdateAgentHeartbeatParams) (int64, error)
+	DeregisterAgent(ctx context.Context, id uuid.UUID) error
+	BumpAgentConfigRevision(ctx context.Context, id uuid.UUID) error
+	ListAgents(ctx context.Context) ([]queries.Agent, error)
+	ListAgentsByNamespace(ctx context.Context, namespace string) ([]queries.Agent, error)
+
+	// Config Schema
+	GetConfigSchema(ctx context.Context, namespace string) (queries.ConfigSchema, error)
+	UpsertConfigSchema(ctx context.Context, arg queries.UpsertConfigSchemaParams) (queries.ConfigSchema, error)
+
+	// Config Proposal
+	CreateConfigProposal(ctx context.Context, arg queries.CreateConfigProposalParams) (queries.ConfigProposal, error)
+	GetConfigProposalForUpdate(ctx context.Context, arg queries.GetConfigProposalForUpdateParams) (queries.ConfigProposal, error)
+	ListConfigProposals(ctx context.Context, arg queries.ListConfigProposalsParams) ([]queries.ConfigProposal, error)
+	ApproveConfigProposal(ctx context.Context, arg queries.ApproveConfigProposalParams) (queries.ConfigProposal, error)
+	ExpireConfigProposals(ctx context.Context, now time.Time) ([]queries.ConfigProposal, error)
+
+	// Agent Config Override
+	GetAgentConfigOverride(ctx context.Context, agentID uuid.UUID) (queries.AgentConfigOverride, error)
+	UpsertAgentConfigOverride(ctx context.Context, arg queries.UpsertAgentConfigOverrideParams) (queries.AgentConfigOverride, error)
+
+	// Audit
+	CreateAuditEvent(ctx context.Context, arg queries.CreateAuditEventParams) error
+	ListAuditEvents(ctx context.Context, arg queries.ListAuditEventsParams) ([]queries.AuditEvent, error)
+	ListAuditEventsAfter(ctx context.Context, arg queries.ListAuditEventsAfterParams) ([]queries.AuditEvent, error)
+	CountAuditEvents(ctx context.Context, arg queries.CountAuditEventsParams) (int64, error)
+
+	// API Key
+	CreateAPIKey(ctx context.Context, arg queries.CreateAPIKeyParams) (queries.ApiKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (queries.ApiKey, error)
+	GetAPIKeyByName(ctx context.Context, name string) (queries.ApiKey, error)
+	ListAPIKeys(ctx context.Context) ([]queries.ApiKey, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) (queries.ApiKey, error)
+
+	// Webhook
+	CreateWebhook(ctx context.Context, arg queries.CreateWebhookParams) (queries.Webhook, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (queries.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]queries.Webhook, error)
+	UpdateWebhook(ctx context.Context, arg queries.UpdateWebhookParams) (queries.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) (queries.Webhook, error)
+	EnqueueWebhookDeliveries(ctx context.Context, arg queries.EnqueueWebhookDeliveriesParams) (int64, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg queries.ClaimWebhookDeliveriesParams) ([]queries.ClaimWebhookDeliveriesRow, error)
+	MarkWebhookDeliveryDelivered(ctx context.Context, arg queries.MarkWebhookDeliveryDeliveredParams) error
+	MarkWebhookDeliveryFailed(ctx context.Context, arg queries.MarkWebhookDeliveryFailedParams) error
+	ListWebhookDeliveries(ctx context.Context, arg queries.ListWebhookDeliveriesParams) ([]queries.WebhookDelivery, error)
+	CountWebhookDeliveries(ctx context.Context, arg queries.CountWebhookDeliveriesParams) (int64, error)
 }
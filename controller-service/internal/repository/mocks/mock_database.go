@@ -9,6 +9,7 @@ import (
 	queries "controller-service/internal/repository/sqlc"
 	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -37,19 +38,167 @@ func (m *MockIRepository) EXPECT() *MockIRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AbortCanaryGlobalConfigs mocks base method.
+func (m *MockIRepository) AbortCanaryGlobalConfigs(ctx context.Context, namespace string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortCanaryGlobalConfigs", ctx, namespace)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AbortCanaryGlobalConfigs indicates an expected call of AbortCanaryGlobalConfigs.
+func (mr *MockIRepositoryMockRecorder) AbortCanaryGlobalConfigs(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortCanaryGlobalConfigs", reflect.TypeOf((*MockIRepository)(nil).AbortCanaryGlobalConfigs), ctx, namespace)
+}
+
+// ApproveConfigProposal mocks base method.
+func (m *MockIRepository) ApproveConfigProposal(ctx context.Context, arg queries.ApproveConfigProposalParams) (queries.ConfigProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveConfigProposal", ctx, arg)
+	ret0, _ := ret[0].(queries.ConfigProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveConfigProposal indicates an expected call of ApproveConfigProposal.
+func (mr *MockIRepositoryMockRecorder) ApproveConfigProposal(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveConfigProposal", reflect.TypeOf((*MockIRepository)(nil).ApproveConfigProposal), ctx, arg)
+}
+
+// BumpAgentConfigRevision mocks base method.
+func (m *MockIRepository) BumpAgentConfigRevision(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BumpAgentConfigRevision", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BumpAgentConfigRevision indicates an expected call of BumpAgentConfigRevision.
+func (mr *MockIRepositoryMockRecorder) BumpAgentConfigRevision(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BumpAgentConfigRevision", reflect.TypeOf((*MockIRepository)(nil).BumpAgentConfigRevision), ctx, id)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockIRepository) ClaimWebhookDeliveries(ctx context.Context, arg queries.ClaimWebhookDeliveriesParams) ([]queries.ClaimWebhookDeliveriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]queries.ClaimWebhookDeliveriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockIRepositoryMockRecorder) ClaimWebhookDeliveries(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockIRepository)(nil).ClaimWebhookDeliveries), ctx, arg)
+}
+
+// CountAuditEvents mocks base method.
+func (m *MockIRepository) CountAuditEvents(ctx context.Context, arg queries.CountAuditEventsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAuditEvents", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAuditEvents indicates an expected call of CountAuditEvents.
+func (mr *MockIRepositoryMockRecorder) CountAuditEvents(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAuditEvents", reflect.TypeOf((*MockIRepository)(nil).CountAuditEvents), ctx, arg)
+}
+
+// CountGlobalConfigs mocks base method.
+func (m *MockIRepository) CountGlobalConfigs(ctx context.Context, namespace string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountGlobalConfigs", ctx, namespace)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountGlobalConfigs indicates an expected call of CountGlobalConfigs.
+func (mr *MockIRepositoryMockRecorder) CountGlobalConfigs(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountGlobalConfigs", reflect.TypeOf((*MockIRepository)(nil).CountGlobalConfigs), ctx, namespace)
+}
+
+// CountWebhookDeliveries mocks base method.
+func (m *MockIRepository) CountWebhookDeliveries(ctx context.Context, arg queries.CountWebhookDeliveriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWebhookDeliveries indicates an expected call of CountWebhookDeliveries.
+func (mr *MockIRepositoryMockRecorder) CountWebhookDeliveries(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWebhookDeliveries", reflect.TypeOf((*MockIRepository)(nil).CountWebhookDeliveries), ctx, arg)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockIRepository) CreateAPIKey(ctx context.Context, arg queries.CreateAPIKeyParams) (queries.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, arg)
+	ret0, _ := ret[0].(queries.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockIRepositoryMockRecorder) CreateAPIKey(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockIRepository)(nil).CreateAPIKey), ctx, arg)
+}
+
 // CreateAgent mocks base method.
-func (m *MockIRepository) CreateAgent(ctx context.Context, name string) (uuid.UUID, error) {
+func (m *MockIRepository) CreateAgent(ctx context.Context, arg queries.CreateAgentParams) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAgent", ctx, name)
+	ret := m.ctrl.Call(m, "CreateAgent", ctx, arg)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateAgent indicates an expected call of CreateAgent.
-func (mr *MockIRepositoryMockRecorder) CreateAgent(ctx, name interface{}) *gomock.Call {
+func (mr *MockIRepositoryMockRecorder) CreateAgent(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAgent", reflect.TypeOf((*MockIRepository)(nil).CreateAgent), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAgent", reflect.TypeOf((*MockIRepository)(nil).CreateAgent), ctx, arg)
+}
+
+// CreateAuditEvent mocks base method.
+func (m *MockIRepository) CreateAuditEvent(ctx context.Context, arg queries.CreateAuditEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockIRepositoryMockRecorder) CreateAuditEvent(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockIRepository)(nil).CreateAuditEvent), ctx, arg)
+}
+
+// CreateConfigProposal mocks base method.
+func (m *MockIRepository) CreateConfigProposal(ctx context.Context, arg queries.CreateConfigProposalParams) (queries.ConfigProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigProposal", ctx, arg)
+	ret0, _ := ret[0].(queries.ConfigProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfigProposal indicates an expected call of CreateConfigProposal.
+func (mr *MockIRepositoryMockRecorder) CreateConfigProposal(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigProposal", reflect.TypeOf((*MockIRepository)(nil).CreateConfigProposal), ctx, arg)
 }
 
 // CreateGlobalConfig mocks base method.
@@ -67,19 +216,675 @@ func (mr *MockIRepositoryMockRecorder) CreateGlobalConfig(ctx, arg interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGlobalConfig", reflect.TypeOf((*MockIRepository)(nil).CreateGlobalConfig), ctx, arg)
 }
 
+// CreateScheduledConfig mocks base method.
+func (m *MockIRepository) CreateScheduledConfig(ctx context.Context, arg queries.CreateScheduledConfigParams) (queries.ScheduledConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledConfig", ctx, arg)
+	ret0, _ := ret[0].(queries.ScheduledConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledConfig indicates an expected call of CreateScheduledConfig.
+func (mr *MockIRepositoryMockRecorder) CreateScheduledConfig(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledConfig", reflect.TypeOf((*MockIRepository)(nil).CreateScheduledConfig), ctx, arg)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockIRepository) CreateWebhook(ctx context.Context, arg queries.CreateWebhookParams) (queries.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, arg)
+	ret0, _ := ret[0].(queries.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockIRepositoryMockRecorder) CreateWebhook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockIRepository)(nil).CreateWebhook), ctx, arg)
+}
+
+// DeleteScheduledConfig mocks base method.
+func (m *MockIRepository) DeleteScheduledConfig(ctx context.Context, arg queries.DeleteScheduledConfigParams) (queries.ScheduledConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledConfig", ctx, arg)
+	ret0, _ := ret[0].(queries.ScheduledConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteScheduledConfig indicates an expected call of DeleteScheduledConfig.
+func (mr *MockIRepositoryMockRecorder) DeleteScheduledConfig(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledConfig", reflect.TypeOf((*MockIRepository)(nil).DeleteScheduledConfig), ctx, arg)
+}
+
+// DeleteStaleAgents mocks base method.
+func (m *MockIRepository) DeleteStaleAgents(ctx context.Context, maxIdleDays int32) ([]queries.Agent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleAgents", ctx, maxIdleDays)
+	ret0, _ := ret[0].([]queries.Agent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStaleAgents indicates an expected call of DeleteStaleAgents.
+func (mr *MockIRepositoryMockRecorder) DeleteStaleAgents(ctx, maxIdleDays interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleAgents", reflect.TypeOf((*MockIRepository)(nil).DeleteStaleAgents), ctx, maxIdleDays)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockIRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) (queries.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(queries.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockIRepositoryMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockIRepository)(nil).DeleteWebhook), ctx, id)
+}
+
+// DeregisterAgent mocks base method.
+func (m *MockIRepository) DeregisterAgent(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeregisterAgent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeregisterAgent indicates an expected call of DeregisterAgent.
+func (mr *MockIRepositoryMockRecorder) DeregisterAgent(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterAgent", reflect.TypeOf((*MockIRepository)(nil).DeregisterAgent), ctx, id)
+}
+
+// EnqueueWebhookDeliveries mocks base method.
+func (m *MockIRepository) EnqueueWebhookDeliveries(ctx context.Context, arg queries.EnqueueWebhookDeliveriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueWebhookDeliveries indicates an expected call of EnqueueWebhookDeliveries.
+func (mr *MockIRepositoryMockRecorder) EnqueueWebhookDeliveries(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookDeliveries", reflect.TypeOf((*MockIRepository)(nil).EnqueueWebhookDeliveries), ctx, arg)
+}
+
+// ExpireConfigProposals mocks base method.
+func (m *MockIRepository) ExpireConfigProposals(ctx context.Context, now time.Time) ([]queries.ConfigProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireConfigProposals", ctx, now)
+	ret0, _ := ret[0].([]queries.ConfigProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireConfigProposals indicates an expected call of ExpireConfigProposals.
+func (mr *MockIRepositoryMockRecorder) ExpireConfigProposals(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireConfigProposals", reflect.TypeOf((*MockIRepository)(nil).ExpireConfigProposals), ctx, now)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockIRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (queries.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, keyHash)
+	ret0, _ := ret[0].(queries.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockIRepositoryMockRecorder) GetAPIKeyByHash(ctx, keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockIRepository)(nil).GetAPIKeyByHash), ctx, keyHash)
+}
+
+// GetAPIKeyByName mocks base method.
+func (m *MockIRepository) GetAPIKeyByName(ctx context.Context, name string) (queries.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByName", ctx, name)
+	ret0, _ := ret[0].(queries.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByName indicates an expected call of GetAPIKeyByName.
+func (mr *MockIRepositoryMockRecorder) GetAPIKeyByName(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByName", reflect.TypeOf((*MockIRepository)(nil).GetAPIKeyByName), ctx, name)
+}
+
+// GetAgent mocks base method.
+func (m *MockIRepository) GetAgent(ctx context.Context, id uuid.UUID) (queries.Agent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgent", ctx, id)
+	ret0, _ := ret[0].(queries.Agent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgent indicates an expected call of GetAgent.
+func (mr *MockIRepositoryMockRecorder) GetAgent(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgent", reflect.TypeOf((*MockIRepository)(nil).GetAgent), ctx, id)
+}
+
+// GetAgentConfigOverride mocks base method.
+func (m *MockIRepository) GetAgentConfigOverride(ctx context.Context, agentID uuid.UUID) (queries.AgentConfigOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgentConfigOverride", ctx, agentID)
+	ret0, _ := ret[0].(queries.AgentConfigOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentConfigOverride indicates an expected call of GetAgentConfigOverride.
+func (mr *MockIRepositoryMockRecorder) GetAgentConfigOverride(ctx, agentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentConfigOverride", reflect.TypeOf((*MockIRepository)(nil).GetAgentConfigOverride), ctx, agentID)
+}
+
+// GetConfigProposalForUpdate mocks base method.
+func (m *MockIRepository) GetConfigProposalForUpdate(ctx context.Context, arg queries.GetConfigProposalForUpdateParams) (queries.ConfigProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigProposalForUpdate", ctx, arg)
+	ret0, _ := ret[0].(queries.ConfigProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigProposalForUpdate indicates an expected call of GetConfigProposalForUpdate.
+func (mr *MockIRepositoryMockRecorder) GetConfigProposalForUpdate(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigProposalForUpdate", reflect.TypeOf((*MockIRepository)(nil).GetConfigProposalForUpdate), ctx, arg)
+}
+
+// GetConfigSchema mocks base method.
+func (m *MockIRepository) GetConfigSchema(ctx context.Context, namespace string) (queries.ConfigSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigSchema", ctx, namespace)
+	ret0, _ := ret[0].(queries.ConfigSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigSchema indicates an expected call of GetConfigSchema.
+func (mr *MockIRepositoryMockRecorder) GetConfigSchema(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigSchema", reflect.TypeOf((*MockIRepository)(nil).GetConfigSchema), ctx, namespace)
+}
+
+// GetCurrentVersionGlobalConfig mocks base method.
+func (m *MockIRepository) GetCurrentVersionGlobalConfig(ctx context.Context, namespace string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentVersionGlobalConfig", ctx, namespace)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentVersionGlobalConfig indicates an expected call of GetCurrentVersionGlobalConfig.
+func (mr *MockIRepositoryMockRecorder) GetCurrentVersionGlobalConfig(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentVersionGlobalConfig", reflect.TypeOf((*MockIRepository)(nil).GetCurrentVersionGlobalConfig), ctx, namespace)
+}
+
+// GetDueScheduledConfig mocks base method.
+func (m *MockIRepository) GetDueScheduledConfig(ctx context.Context, now time.Time) (queries.ScheduledConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueScheduledConfig", ctx, now)
+	ret0, _ := ret[0].(queries.ScheduledConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueScheduledConfig indicates an expected call of GetDueScheduledConfig.
+func (mr *MockIRepositoryMockRecorder) GetDueScheduledConfig(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueScheduledConfig", reflect.TypeOf((*MockIRepository)(nil).GetDueScheduledConfig), ctx, now)
+}
+
+// GetGlobalConfigByVersion mocks base method.
+func (m *MockIRepository) GetGlobalConfigByVersion(ctx context.Context, arg queries.GetGlobalConfigByVersionParams) (queries.GlobalConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalConfigByVersion", ctx, arg)
+	ret0, _ := ret[0].(queries.GlobalConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalConfigByVersion indicates an expected call of GetGlobalConfigByVersion.
+func (mr *MockIRepositoryMockRecorder) GetGlobalConfigByVersion(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalConfigByVersion", reflect.TypeOf((*MockIRepository)(nil).GetGlobalConfigByVersion), ctx, arg)
+}
+
+// GetLatestCanaryGlobalConfig mocks base method.
+func (m *MockIRepository) GetLatestCanaryGlobalConfig(ctx context.Context, namespace string) (queries.GlobalConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestCanaryGlobalConfig", ctx, namespace)
+	ret0, _ := ret[0].(queries.GlobalConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestCanaryGlobalConfig indicates an expected call of GetLatestCanaryGlobalConfig.
+func (mr *MockIRepositoryMockRecorder) GetLatestCanaryGlobalConfig(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestCanaryGlobalConfig", reflect.TypeOf((*MockIRepository)(nil).GetLatestCanaryGlobalConfig), ctx, namespace)
+}
+
 // GetLatestVersionGlobalConfig mocks base method.
-func (m *MockIRepository) GetLatestVersionGlobalConfig(ctx context.Context) (queries.GlobalConfig, error) {
+func (m *MockIRepository) GetLatestVersionGlobalConfig(ctx context.Context, namespace string) (queries.GlobalConfig, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLatestVersionGlobalConfig", ctx)
+	ret := m.ctrl.Call(m, "GetLatestVersionGlobalConfig", ctx, namespace)
 	ret0, _ := ret[0].(queries.GlobalConfig)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLatestVersionGlobalConfig indicates an expected call of GetLatestVersionGlobalConfig.
-func (mr *MockIRepositoryMockRecorder) GetLatestVersionGlobalConfig(ctx interface{}) *gomock.Call {
+func (mr *MockIRepositoryMockRecorder) GetLatestVersionGlobalConfig(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestVersionGlobalConfig", reflect.TypeOf((*MockIRepository)(nil).GetLatestVersionGlobalConfig), ctx, namespace)
+}
+
+// GetMaxVersionGlobalConfig mocks base method.
+func (m *MockIRepository) GetMaxVersionGlobalConfig(ctx context.Context, namespace string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxVersionGlobalConfig", ctx, namespace)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaxVersionGlobalConfig indicates an expected call of GetMaxVersionGlobalConfig.
+func (mr *MockIRepositoryMockRecorder) GetMaxVersionGlobalConfig(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxVersionGlobalConfig", reflect.TypeOf((*MockIRepository)(nil).GetMaxVersionGlobalConfig), ctx, namespace)
+}
+
+// GetRevisionGlobalConfig mocks base method.
+func (m *MockIRepository) GetRevisionGlobalConfig(ctx context.Context, namespace string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisionGlobalConfig", ctx, namespace)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisionGlobalConfig indicates an expected call of GetRevisionGlobalConfig.
+func (mr *MockIRepositoryMockRecorder) GetRevisionGlobalConfig(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisionGlobalConfig", reflect.TypeOf((*MockIRepository)(nil).GetRevisionGlobalConfig), ctx, namespace)
+}
+
+// GetScheduledConfig mocks base method.
+func (m *MockIRepository) GetScheduledConfig(ctx context.Context, arg queries.GetScheduledConfigParams) (queries.ScheduledConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledConfig", ctx, arg)
+	ret0, _ := ret[0].(queries.ScheduledConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledConfig indicates an expected call of GetScheduledConfig.
+func (mr *MockIRepositoryMockRecorder) GetScheduledConfig(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledConfig", reflect.TypeOf((*MockIRepository)(nil).GetScheduledConfig), ctx, arg)
+}
+
+// GetWebhook mocks base method.
+func (m *MockIRepository) GetWebhook(ctx context.Context, id uuid.UUID) (queries.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(queries.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockIRepositoryMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockIRepository)(nil).GetWebhook), ctx, id)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockIRepository) ListAPIKeys(ctx context.Context) ([]queries.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx)
+	ret0, _ := ret[0].([]queries.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockIRepositoryMockRecorder) ListAPIKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockIRepository)(nil).ListAPIKeys), ctx)
+}
+
+// ListAgents mocks base method.
+func (m *MockIRepository) ListAgents(ctx context.Context) ([]queries.Agent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAgents", ctx)
+	ret0, _ := ret[0].([]queries.Agent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAgents indicates an expected call of ListAgents.
+func (mr *MockIRepositoryMockRecorder) ListAgents(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAgents", reflect.TypeOf((*MockIRepository)(nil).ListAgents), ctx)
+}
+
+// ListAgentsByNamespace mocks base method.
+func (m *MockIRepository) ListAgentsByNamespace(ctx context.Context, namespace string) ([]queries.Agent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAgentsByNamespace", ctx, namespace)
+	ret0, _ := ret[0].([]queries.Agent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAgentsByNamespace indicates an expected call of ListAgentsByNamespace.
+func (mr *MockIRepositoryMockRecorder) ListAgentsByNamespace(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAgentsByNamespace", reflect.TypeOf((*MockIRepository)(nil).ListAgentsByNamespace), ctx, namespace)
+}
+
+// ListAllGlobalConfigs mocks base method.
+func (m *MockIRepository) ListAllGlobalConfigs(ctx context.Context, namespace string) ([]queries.GlobalConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllGlobalConfigs", ctx, namespace)
+	ret0, _ := ret[0].([]queries.GlobalConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllGlobalConfigs indicates an expected call of ListAllGlobalConfigs.
+func (mr *MockIRepositoryMockRecorder) ListAllGlobalConfigs(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllGlobalConfigs", reflect.TypeOf((*MockIRepository)(nil).ListAllGlobalConfigs), ctx, namespace)
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockIRepository) ListAuditEvents(ctx context.Context, arg queries.ListAuditEventsParams) ([]queries.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", ctx, arg)
+	ret0, _ := ret[0].([]queries.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockIRepositoryMockRecorder) ListAuditEvents(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockIRepository)(nil).ListAuditEvents), ctx, arg)
+}
+
+// ListAuditEventsAfter mocks base method.
+func (m *MockIRepository) ListAuditEventsAfter(ctx context.Context, arg queries.ListAuditEventsAfterParams) ([]queries.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEventsAfter", ctx, arg)
+	ret0, _ := ret[0].([]queries.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEventsAfter indicates an expected call of ListAuditEventsAfter.
+func (mr *MockIRepositoryMockRecorder) ListAuditEventsAfter(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEventsAfter", reflect.TypeOf((*MockIRepository)(nil).ListAuditEventsAfter), ctx, arg)
+}
+
+// ListConfigProposals mocks base method.
+func (m *MockIRepository) ListConfigProposals(ctx context.Context, arg queries.ListConfigProposalsParams) ([]queries.ConfigProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigProposals", ctx, arg)
+	ret0, _ := ret[0].([]queries.ConfigProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfigProposals indicates an expected call of ListConfigProposals.
+func (mr *MockIRepositoryMockRecorder) ListConfigProposals(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigProposals", reflect.TypeOf((*MockIRepository)(nil).ListConfigProposals), ctx, arg)
+}
+
+// ListGlobalConfigNamespaces mocks base method.
+func (m *MockIRepository) ListGlobalConfigNamespaces(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGlobalConfigNamespaces", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGlobalConfigNamespaces indicates an expected call of ListGlobalConfigNamespaces.
+func (mr *MockIRepositoryMockRecorder) ListGlobalConfigNamespaces(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGlobalConfigNamespaces", reflect.TypeOf((*MockIRepository)(nil).ListGlobalConfigNamespaces), ctx)
+}
+
+// ListGlobalConfigs mocks base method.
+func (m *MockIRepository) ListGlobalConfigs(ctx context.Context, arg queries.ListGlobalConfigsParams) ([]queries.GlobalConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGlobalConfigs", ctx, arg)
+	ret0, _ := ret[0].([]queries.GlobalConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGlobalConfigs indicates an expected call of ListGlobalConfigs.
+func (mr *MockIRepositoryMockRecorder) ListGlobalConfigs(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGlobalConfigs", reflect.TypeOf((*MockIRepository)(nil).ListGlobalConfigs), ctx, arg)
+}
+
+// ListScheduledConfigs mocks base method.
+func (m *MockIRepository) ListScheduledConfigs(ctx context.Context, namespace string) ([]queries.ScheduledConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledConfigs", ctx, namespace)
+	ret0, _ := ret[0].([]queries.ScheduledConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledConfigs indicates an expected call of ListScheduledConfigs.
+func (mr *MockIRepositoryMockRecorder) ListScheduledConfigs(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledConfigs", reflect.TypeOf((*MockIRepository)(nil).ListScheduledConfigs), ctx, namespace)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockIRepository) ListWebhookDeliveries(ctx context.Context, arg queries.ListWebhookDeliveriesParams) ([]queries.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]queries.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockIRepositoryMockRecorder) ListWebhookDeliveries(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockIRepository)(nil).ListWebhookDeliveries), ctx, arg)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockIRepository) ListWebhooks(ctx context.Context) ([]queries.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]queries.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockIRepositoryMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockIRepository)(nil).ListWebhooks), ctx)
+}
+
+// MarkWebhookDeliveryDelivered mocks base method.
+func (m *MockIRepository) MarkWebhookDeliveryDelivered(ctx context.Context, arg queries.MarkWebhookDeliveryDeliveredParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryDelivered", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliveryDelivered indicates an expected call of MarkWebhookDeliveryDelivered.
+func (mr *MockIRepositoryMockRecorder) MarkWebhookDeliveryDelivered(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryDelivered", reflect.TypeOf((*MockIRepository)(nil).MarkWebhookDeliveryDelivered), ctx, arg)
+}
+
+// MarkWebhookDeliveryFailed mocks base method.
+func (m *MockIRepository) MarkWebhookDeliveryFailed(ctx context.Context, arg queries.MarkWebhookDeliveryFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryFailed", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliveryFailed indicates an expected call of MarkWebhookDeliveryFailed.
+func (mr *MockIRepositoryMockRecorder) MarkWebhookDeliveryFailed(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryFailed", reflect.TypeOf((*MockIRepository)(nil).MarkWebhookDeliveryFailed), ctx, arg)
+}
+
+// NotifyGlobalConfigUpdated mocks base method.
+func (m *MockIRepository) NotifyGlobalConfigUpdated(ctx context.Context, payload string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyGlobalConfigUpdated", ctx, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyGlobalConfigUpdated indicates an expected call of NotifyGlobalConfigUpdated.
+func (mr *MockIRepositoryMockRecorder) NotifyGlobalConfigUpdated(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyGlobalConfigUpdated", reflect.TypeOf((*MockIRepository)(nil).NotifyGlobalConfigUpdated), ctx, payload)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockIRepository) RevokeAPIKey(ctx context.Context, id uuid.UUID) (queries.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(queries.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockIRepositoryMockRecorder) RevokeAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockIRepository)(nil).RevokeAPIKey), ctx, id)
+}
+
+// UpdateAgentHeartbeat mocks base method.
+func (m *MockIRepository) UpdateAgentHeartbeat(ctx context.Context, arg queries.UpdateAgentHeartbeatParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAgentHeartbeat", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAgentHeartbeat indicates an expected call of UpdateAgentHeartbeat.
+func (mr *MockIRepositoryMockRecorder) UpdateAgentHeartbeat(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentHeartbeat", reflect.TypeOf((*MockIRepository)(nil).UpdateAgentHeartbeat), ctx, arg)
+}
+
+// UpdateGlobalConfigRollout mocks base method.
+func (m *MockIRepository) UpdateGlobalConfigRollout(ctx context.Context, arg queries.UpdateGlobalConfigRolloutParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGlobalConfigRollout", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGlobalConfigRollout indicates an expected call of UpdateGlobalConfigRollout.
+func (mr *MockIRepositoryMockRecorder) UpdateGlobalConfigRollout(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGlobalConfigRollout", reflect.TypeOf((*MockIRepository)(nil).UpdateGlobalConfigRollout), ctx, arg)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockIRepository) UpdateWebhook(ctx context.Context, arg queries.UpdateWebhookParams) (queries.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, arg)
+	ret0, _ := ret[0].(queries.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockIRepositoryMockRecorder) UpdateWebhook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockIRepository)(nil).UpdateWebhook), ctx, arg)
+}
+
+// UpsertAgent mocks base method.
+func (m *MockIRepository) UpsertAgent(ctx context.Context, arg queries.UpsertAgentParams) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAgent", ctx, arg)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertAgent indicates an expected call of UpsertAgent.
+func (mr *MockIRepositoryMockRecorder) UpsertAgent(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAgent", reflect.TypeOf((*MockIRepository)(nil).UpsertAgent), ctx, arg)
+}
+
+// UpsertAgentConfigOverride mocks base method.
+func (m *MockIRepository) UpsertAgentConfigOverride(ctx context.Context, arg queries.UpsertAgentConfigOverrideParams) (queries.AgentConfigOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAgentConfigOverride", ctx, arg)
+	ret0, _ := ret[0].(queries.AgentConfigOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertAgentConfigOverride indicates an expected call of UpsertAgentConfigOverride.
+func (mr *MockIRepositoryMockRecorder) UpsertAgentConfigOverride(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAgentConfigOverride", reflect.TypeOf((*MockIRepository)(nil).UpsertAgentConfigOverride), ctx, arg)
+}
+
+// UpsertConfigSchema mocks base method.
+func (m *MockIRepository) UpsertConfigSchema(ctx context.Context, arg queries.UpsertConfigSchemaParams) (queries.ConfigSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertConfigSchema", ctx, arg)
+	ret0, _ := ret[0].(queries.ConfigSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertConfigSchema indicates an expected call of UpsertConfigSchema.
+func (mr *MockIRepositoryMockRecorder) UpsertConfigSchema(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestVersionGlobalConfig", reflect.TypeOf((*MockIRepository)(nil).GetLatestVersionGlobalConfig), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertConfigSchema", reflect.TypeOf((*MockIRepository)(nil).UpsertConfigSchema), ctx, arg)
 }
 
 // WithTx mocks base method.
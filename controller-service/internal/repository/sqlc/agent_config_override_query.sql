@@ -0,0 +1,16 @@
+-- name: GetAgentConfigOverride :one
+SELECT * 
+FROM 
+    agent_config_overrides 
+WHERE 
+    agent_id = $1;
+
+-- name: UpsertAgentConfigOverride :one
+INSERT INTO agent_config_overrides (agent_id, config, version)
+VALUES ($1, $2, 1)
+ON CONFLICT (agent_id) DO UPDATE 
+SET 
+    config = EXCLUDED.config,
+    version = agent_config_overrides.version + 1,
+    updated_at = now()
+RETURNING *;
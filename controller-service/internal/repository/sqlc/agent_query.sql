@@ -1,2 +1,65 @@
 -- name: CreateAgent :one
-INSERT INTO agents (name) VALUES ($1) RETURNING id;
\ No newline at end of file
+INSERT INTO agents (name, namespace, labels, registered_by) VALUES ($1, $2, $3, $4) RETURNING id;
+
+-- name: UpsertAgent :one
+INSERT INTO agents (id, name, namespace, labels, registered_by) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, namespace = EXCLUDED.namespace, labels = EXCLUDED.labels, deregistered_at = NULL,
+    config_revision = CASE WHEN agents.namespace <> EXCLUDED.namespace OR agents.labels <> EXCLUDED.labels THEN nextval('config_revision_seq') ELSE agents.config_revision END,
+    registered_by = EXCLUDED.registered_by
+WHERE agents.registered_by IN ('', EXCLUDED.registered_by)
+RETURNING id;
+
+-- name: GetAgent :one
+SELECT * 
+FROM 
+    agents 
+WHERE 
+    id = $1;
+
+-- name: DeleteStaleAgents :many
+DELETE FROM agents
+WHERE 
+    COALESCE(last_seen_at, created_at) < now() - make_interval(days => sqlc.arg(max_idle_days)::int)
+RETURNING *;
+
+-- name: UpdateAgentHeartbeat :execrows
+UPDATE agents
+SET 
+    last_seen_at = now(),
+    applied_at = CASE WHEN $2::bigint IS NOT NULL AND applied_version IS DISTINCT FROM $2 THEN now() ELSE applied_at END,
+    applied_version = COALESCE($2, applied_version),
+    worker_healthy = $3,
+    build_info = $4,
+    deregistered_at = NULL
+WHERE 
+    id = $1 AND registered_by IN ('', $5);
+
+-- name: BumpAgentConfigRevision :exec
+UPDATE agents
+SET 
+    config_revision = nextval('config_revision_seq')
+WHERE 
+    id = $1;
+
+-- name: DeregisterAgent :exec
+UPDATE agents
+SET 
+    deregistered_at = now()
+WHERE 
+    id = $1;
+
+-- name: ListAgents :many
+SELECT * 
+FROM 
+    agents 
+ORDER BY 
+    created_at DESC;
+
+-- name: ListAgentsByNamespace :many
+SELECT * 
+FROM 
+    agents 
+WHERE 
+    namespace = $1 
+ORDER BY 
+    created_at DESC;
@@ -7,16 +7,286 @@ package queries
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 
 	"github.com/google/uuid"
 )
 
+const bumpAgentConfigRevision = `-- name: BumpAgentConfigRevision :exec
+UPDATE agents
+SET 
+    config_revision = nextval('config_revision_seq')
+WHERE 
+    id = $1
+`
+
+func (q *Queries) BumpAgentConfigRevision(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, bumpAgentConfigRevision, id)
+	return err
+}
+
 const createAgent = `-- name: CreateAgent :one
-INSERT INTO agents (name) VALUES ($1) RETURNING id
+INSERT INTO agents (name, namespace, labels, registered_by) VALUES ($1, $2, $3, $4) RETURNING id
+`
+
+type CreateAgentParams struct {
+	Name         string
+	Namespace    string
+	Labels       json.RawMessage
+	RegisteredBy string
+}
+
+func (q *Queries) CreateAgent(ctx context.Context, arg CreateAgentParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, createAgent,
+		arg.Name,
+		arg.Namespace,
+		arg.Labels,
+		arg.RegisteredBy,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteStaleAgents = `-- name: DeleteStaleAgents :many
+DELETE FROM agents
+WHERE 
+    COALESCE(last_seen_at, created_at) < now() - make_interval(days => $1::int)
+RETURNING id, name, created_at, last_seen_at, applied_version, worker_healthy, build_info, applied_at, namespace, config_revision, labels, registered_by, deregistered_at
+`
+
+func (q *Queries) DeleteStaleAgents(ctx context.Context, maxIdleDays int32) ([]Agent, error) {
+	rows, err := q.db.QueryContext(ctx, deleteStaleAgents, maxIdleDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Agent
+	for rows.Next() {
+		var i Agent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.LastSeenAt,
+			&i.AppliedVersion,
+			&i.WorkerHealthy,
+			&i.BuildInfo,
+			&i.AppliedAt,
+			&i.Namespace,
+			&i.ConfigRevision,
+			&i.Labels,
+			&i.RegisteredBy,
+			&i.DeregisteredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deregisterAgent = `-- name: DeregisterAgent :exec
+UPDATE agents
+SET 
+    deregistered_at = now()
+WHERE 
+    id = $1
+`
+
+func (q *Queries) DeregisterAgent(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deregisterAgent, id)
+	return err
+}
+
+const getAgent = `-- name: GetAgent :one
+SELECT id, name, created_at, last_seen_at, applied_version, worker_healthy, build_info, applied_at, namespace, config_revision, labels, registered_by, deregistered_at 
+FROM 
+    agents 
+WHERE 
+    id = $1
 `
 
-func (q *Queries) CreateAgent(ctx context.Context, name string) (uuid.UUID, error) {
-	row := q.db.QueryRowContext(ctx, createAgent, name)
+func (q *Queries) GetAgent(ctx context.Context, id uuid.UUID) (Agent, error) {
+	row := q.db.QueryRowContext(ctx, getAgent, id)
+	var i Agent
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastSeenAt,
+		&i.AppliedVersion,
+		&i.WorkerHealthy,
+		&i.BuildInfo,
+		&i.AppliedAt,
+		&i.Namespace,
+		&i.ConfigRevision,
+		&i.Labels,
+		&i.RegisteredBy,
+		&i.DeregisteredAt,
+	)
+	return i, err
+}
+
+const listAgents = `-- name: ListAgents :many
+SELECT id, name, created_at, last_seen_at, applied_version, worker_healthy, build_info, applied_at, namespace, config_revision, labels, registered_by, deregistered_at 
+FROM 
+    agents 
+ORDER BY 
+    created_at DESC
+`
+
+func (q *Queries) ListAgents(ctx context.Context) ([]Agent, error) {
+	rows, err := q.db.QueryContext(ctx, listAgents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Agent
+	for rows.Next() {
+		var i Agent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.LastSeenAt,
+			&i.AppliedVersion,
+			&i.WorkerHealthy,
+			&i.BuildInfo,
+			&i.AppliedAt,
+			&i.Namespace,
+			&i.ConfigRevision,
+			&i.Labels,
+			&i.RegisteredBy,
+			&i.DeregisteredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAgentsByNamespace = `-- name: ListAgentsByNamespace :many
+SELECT id, name, created_at, last_seen_at, applied_version, worker_healthy, build_info, applied_at, namespace, config_revision, labels, registered_by, deregistered_at 
+FROM 
+    agents 
+WHERE 
+    namespace = $1 
+ORDER BY 
+    created_at DESC
+`
+
+func (q *Queries) ListAgentsByNamespace(ctx context.Context, namespace string) ([]Agent, error) {
+	rows, err := q.db.QueryContext(ctx, listAgentsByNamespace, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Agent
+	for rows.Next() {
+		var i Agent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.LastSeenAt,
+			&i.AppliedVersion,
+			&i.WorkerHealthy,
+			&i.BuildInfo,
+			&i.AppliedAt,
+			&i.Namespace,
+			&i.ConfigRevision,
+			&i.Labels,
+			&i.RegisteredBy,
+			&i.DeregisteredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAgentHeartbeat = `-- name: UpdateAgentHeartbeat :execrows
+UPDATE agents
+SET 
+    last_seen_at = now(),
+    applied_at = CASE WHEN $2::bigint IS NOT NULL AND applied_version IS DISTINCT FROM $2 THEN now() ELSE applied_at END,
+    applied_version = COALESCE($2, applied_version),
+    worker_healthy = $3,
+    build_info = $4,
+    deregistered_at = NULL
+WHERE 
+    id = $1 AND registered_by IN ('', $5)
+`
+
+type UpdateAgentHeartbeatParams struct {
+	ID             uuid.UUID
+	AppliedVersion sql.NullInt64
+	WorkerHealthy  sql.NullBool
+	BuildInfo      json.RawMessage
+	RegisteredBy   string
+}
+
+func (q *Queries) UpdateAgentHeartbeat(ctx context.Context, arg UpdateAgentHeartbeatParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateAgentHeartbeat,
+		arg.ID,
+		arg.AppliedVersion,
+		arg.WorkerHealthy,
+		arg.BuildInfo,
+		arg.RegisteredBy,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertAgent = `-- name: UpsertAgent :one
+INSERT INTO agents (id, name, namespace, labels, registered_by) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, namespace = EXCLUDED.namespace, labels = EXCLUDED.labels, deregistered_at = NULL,
+    config_revision = CASE WHEN agents.namespace <> EXCLUDED.namespace OR agents.labels <> EXCLUDED.labels THEN nextval('config_revision_seq') ELSE agents.config_revision END,
+    registered_by = EXCLUDED.registered_by
+WHERE agents.registered_by IN ('', EXCLUDED.registered_by)
+RETURNING id
+`
+
+type UpsertAgentParams struct {
+	ID           uuid.UUID
+	Name         string
+	Namespace    string
+	Labels       json.RawMessage
+	RegisteredBy string
+}
+
+func (q *Queries) UpsertAgent(ctx context.Context, arg UpsertAgentParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, upsertAgent,
+		arg.ID,
+		arg.Name,
+		arg.Namespace,
+		arg.Labels,
+		arg.RegisteredBy,
+	)
 	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
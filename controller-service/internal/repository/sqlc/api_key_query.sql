@@ -0,0 +1,33 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (name, key_hash, key_prefix, scopes, expires_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetAPIKeyByHash :one
+SELECT * 
+FROM 
+    api_keys 
+WHERE 
+    key_hash = $1;
+
+-- name: GetAPIKeyByName :one
+SELECT * 
+FROM 
+    api_keys 
+WHERE 
+    name = $1;
+
+-- name: ListAPIKeys :many
+SELECT * 
+FROM 
+    api_keys 
+ORDER BY 
+    created_at DESC;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET 
+    revoked_at = COALESCE(revoked_at, now())
+WHERE 
+    id = $1
+RETURNING *;
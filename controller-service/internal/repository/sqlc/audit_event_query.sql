@@ -0,0 +1,40 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (actor, action, resource, source_ip, request_id, before, after)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListAuditEvents :many
+SELECT * 
+FROM 
+    audit_events 
+WHERE 
+    (sqlc.narg(actor)::text IS NULL OR actor = sqlc.narg(actor)) 
+    AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action)) 
+    AND (sqlc.narg(since)::timestamp IS NULL OR created_at >= sqlc.narg(since)) 
+    AND (sqlc.narg(until)::timestamp IS NULL OR created_at < sqlc.narg(until)) 
+ORDER BY 
+    id DESC 
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountAuditEvents :one
+SELECT COUNT(*) 
+FROM 
+    audit_events 
+WHERE 
+    (sqlc.narg(actor)::text IS NULL OR actor = sqlc.narg(actor)) 
+    AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action)) 
+    AND (sqlc.narg(since)::timestamp IS NULL OR created_at >= sqlc.narg(since)) 
+    AND (sqlc.narg(until)::timestamp IS NULL OR created_at < sqlc.narg(until));
+
+-- name: ListAuditEventsAfter :many
+SELECT * 
+FROM 
+    audit_events 
+WHERE 
+    id > sqlc.arg(after_id) 
+    AND (sqlc.narg(actor)::text IS NULL OR actor = sqlc.narg(actor)) 
+    AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action)) 
+    AND (sqlc.narg(since)::timestamp IS NULL OR created_at >= sqlc.narg(since)) 
+    AND (sqlc.narg(until)::timestamp IS NULL OR created_at < sqlc.narg(until)) 
+ORDER BY 
+    id ASC 
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,42 @@
+-- name: CreateConfigProposal :one
+INSERT INTO config_proposals (namespace, kind, agent_id, request, proposed_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetConfigProposalForUpdate :one
+SELECT * 
+FROM 
+    config_proposals 
+WHERE 
+    namespace = $1 AND id = $2 
+FOR UPDATE;
+
+-- name: ListConfigProposals :many
+SELECT * 
+FROM 
+    config_proposals 
+WHERE 
+    namespace = $1 
+    AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)) 
+ORDER BY 
+    created_at DESC;
+
+-- name: ApproveConfigProposal :one
+UPDATE config_proposals
+SET 
+    status = 'approved',
+    approved_by = $2,
+    version = $3,
+    decided_at = now()
+WHERE 
+    id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: ExpireConfigProposals :many
+UPDATE config_proposals
+SET 
+    status = 'expired',
+    decided_at = now()
+WHERE 
+    status = 'pending' AND expires_at <= sqlc.arg(now)::timestamp
+RETURNING *;
@@ -0,0 +1,15 @@
+-- name: GetConfigSchema :one
+SELECT * 
+FROM 
+    config_schemas 
+WHERE 
+    namespace = $1;
+
+-- name: UpsertConfigSchema :one
+INSERT INTO config_schemas (namespace, schema)
+VALUES ($1, $2)
+ON CONFLICT (namespace) DO UPDATE 
+SET 
+    schema = EXCLUDED.schema,
+    updated_at = now()
+RETURNING *;
@@ -5,6 +5,7 @@
 package queries
 
 import (
+	"database/sql"
 	"encoding/json"
 	"time"
 
@@ -12,14 +13,117 @@ import (
 )
 
 type Agent struct {
+	ID             uuid.UUID
+	Name           string
+	CreatedAt      time.Time
+	LastSeenAt     sql.NullTime
+	AppliedVersion sql.NullInt64
+	WorkerHealthy  sql.NullBool
+	BuildInfo      json.RawMessage
+	AppliedAt      sql.NullTime
+	Namespace      string
+	ConfigRevision int64
+	Labels         json.RawMessage
+	RegisteredBy   string
+	DeregisteredAt sql.NullTime
+}
+
+type AgentConfigOverride struct {
+	AgentID   uuid.UUID
+	Config    json.RawMessage
+	Version   int64
+	UpdatedAt time.Time
+}
+
+type ApiKey struct {
 	ID        uuid.UUID
 	Name      string
+	KeyHash   string
+	KeyPrefix string
+	Scopes    []string
+	ExpiresAt sql.NullTime
+	RevokedAt sql.NullTime
 	CreatedAt time.Time
+	CreatedBy string
 }
 
-type GlobalConfig struct {
-	ID        uuid.UUID
-	Config    json.RawMessage
-	Version   int64
+type AuditEvent struct {
+	ID        int64
+	Actor     string
+	Action    string
+	Resource  string
+	SourceIp  string
+	RequestID string
+	Before    json.RawMessage
+	After     json.RawMessage
 	CreatedAt time.Time
 }
+
+type ConfigProposal struct {
+	ID         uuid.UUID
+	Namespace  string
+	Kind       string
+	AgentID    uuid.NullUUID
+	Request    json.RawMessage
+	Status     string
+	ProposedBy string
+	ApprovedBy string
+	Version    int64
+	ExpiresAt  time.Time
+	DecidedAt  sql.NullTime
+	CreatedAt  time.Time
+}
+
+type ConfigSchema struct {
+	Namespace string
+	Schema    json.RawMessage
+	UpdatedAt time.Time
+}
+
+type GlobalConfig struct {
+	ID                uuid.UUID
+	Config            json.RawMessage
+	Version           int64
+	CreatedAt         time.Time
+	Namespace         string
+	Revision          int64
+	Status            string
+	RolloutPercentage int32
+	RolloutSelector   json.RawMessage
+	EffectiveAt       sql.NullTime
+}
+
+type ScheduledConfig struct {
+	ID                uuid.UUID
+	Namespace         string
+	Config            json.RawMessage
+	RolloutPercentage int32
+	RolloutSelector   json.RawMessage
+	EffectiveAt       time.Time
+	CreatedAt         time.Time
+}
+
+type Webhook struct {
+	ID         uuid.UUID
+	Url        string
+	Secret     string
+	Namespaces []string
+	Enabled    bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	WebhookID      uuid.UUID
+	EventID        uuid.UUID
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastStatusCode sql.NullInt32
+	LastError      string
+	DeliveredAt    sql.NullTime
+	CreatedAt      time.Time
+}
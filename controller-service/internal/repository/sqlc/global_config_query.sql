@@ -2,10 +2,105 @@
 SELECT * 
 FROM 
     global_config 
+WHERE 
+    namespace = $1 AND status = 'active' 
 ORDER BY 
     version DESC LIMIT 1;
 
+-- name: GetLatestCanaryGlobalConfig :one
+SELECT * 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1 AND status = 'canary' 
+ORDER BY 
+    version DESC LIMIT 1;
+
+-- name: GetCurrentVersionGlobalConfig :one
+SELECT COALESCE(MAX(version), 0)::BIGINT 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1 AND status <> 'aborted';
+
+-- name: GetMaxVersionGlobalConfig :one
+SELECT COALESCE(MAX(version), 0)::BIGINT 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1;
+
+-- name: GetRevisionGlobalConfig :one
+SELECT COALESCE(MAX(revision), 0)::BIGINT 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1;
+
 -- name: CreateGlobalConfig :one
-INSERT INTO global_config (config, version)
-VALUES ($1, $2)
-RETURNING *;
\ No newline at end of file
+INSERT INTO global_config (namespace, config, version, status, rollout_percentage, rollout_selector, effective_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: UpdateGlobalConfigRollout :execrows
+UPDATE global_config
+SET 
+    status = $3,
+    rollout_percentage = $4,
+    revision = nextval('config_revision_seq')
+WHERE 
+    namespace = $1 AND version = $2 AND status = 'canary';
+
+-- name: AbortCanaryGlobalConfigs :execrows
+UPDATE global_config
+SET 
+    status = 'aborted',
+    revision = nextval('config_revision_seq')
+WHERE 
+    namespace = $1 AND status = 'canary';
+
+-- name: GetGlobalConfigByVersion :one
+SELECT * 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1 AND version = $2 
+LIMIT 1;
+
+-- name: ListGlobalConfigs :many
+SELECT * 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1 
+ORDER BY 
+    version DESC 
+LIMIT $2 OFFSET $3;
+
+-- name: ListAllGlobalConfigs :many
+SELECT * 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1 
+ORDER BY 
+    version;
+
+-- name: ListGlobalConfigNamespaces :many
+SELECT namespace FROM global_config
+UNION
+SELECT namespace FROM config_schemas
+UNION
+SELECT namespace FROM scheduled_configs
+ORDER BY 
+    namespace;
+
+-- name: CountGlobalConfigs :one
+SELECT COUNT(*) 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1;
+
+-- name: NotifyGlobalConfigUpdated :exec
+SELECT pg_notify('global_config_updated', sqlc.arg(payload)::text);
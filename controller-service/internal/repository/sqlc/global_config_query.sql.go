@@ -7,48 +7,380 @@ package queries
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 )
 
+const abortCanaryGlobalConfigs = `-- name: AbortCanaryGlobalConfigs :execrows
+UPDATE global_config
+SET 
+    status = 'aborted',
+    revision = nextval('config_revision_seq')
+WHERE 
+    namespace = $1 AND status = 'canary'
+`
+
+func (q *Queries) AbortCanaryGlobalConfigs(ctx context.Context, namespace string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, abortCanaryGlobalConfigs, namespace)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countGlobalConfigs = `-- name: CountGlobalConfigs :one
+SELECT COUNT(*) 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1
+`
+
+func (q *Queries) CountGlobalConfigs(ctx context.Context, namespace string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGlobalConfigs, namespace)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createGlobalConfig = `-- name: CreateGlobalConfig :one
-INSERT INTO global_config (config, version)
-VALUES ($1, $2)
-RETURNING id, config, version, created_at
+INSERT INTO global_config (namespace, config, version, status, rollout_percentage, rollout_selector, effective_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, config, version, created_at, namespace, revision, status, rollout_percentage, rollout_selector, effective_at
 `
 
 type CreateGlobalConfigParams struct {
-	Config  json.RawMessage
-	Version int64
+	Namespace         string
+	Config            json.RawMessage
+	Version           int64
+	Status            string
+	RolloutPercentage int32
+	RolloutSelector   json.RawMessage
+	EffectiveAt       sql.NullTime
 }
 
 func (q *Queries) CreateGlobalConfig(ctx context.Context, arg CreateGlobalConfigParams) (GlobalConfig, error) {
-	row := q.db.QueryRowContext(ctx, createGlobalConfig, arg.Config, arg.Version)
+	row := q.db.QueryRowContext(ctx, createGlobalConfig,
+		arg.Namespace,
+		arg.Config,
+		arg.Version,
+		arg.Status,
+		arg.RolloutPercentage,
+		arg.RolloutSelector,
+		arg.EffectiveAt,
+	)
 	var i GlobalConfig
 	err := row.Scan(
 		&i.ID,
 		&i.Config,
 		&i.Version,
 		&i.CreatedAt,
+		&i.Namespace,
+		&i.Revision,
+		&i.Status,
+		&i.RolloutPercentage,
+		&i.RolloutSelector,
+		&i.EffectiveAt,
+	)
+	return i, err
+}
+
+const getCurrentVersionGlobalConfig = `-- name: GetCurrentVersionGlobalConfig :one
+SELECT COALESCE(MAX(version), 0)::BIGINT 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1 AND status <> 'aborted'
+`
+
+func (q *Queries) GetCurrentVersionGlobalConfig(ctx context.Context, namespace string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getCurrentVersionGlobalConfig, namespace)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getGlobalConfigByVersion = `-- name: GetGlobalConfigByVersion :one
+SELECT id, config, version, created_at, namespace, revision, status, rollout_percentage, rollout_selector, effective_at 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1 AND version = $2 
+LIMIT 1
+`
+
+type GetGlobalConfigByVersionParams struct {
+	Namespace string
+	Version   int64
+}
+
+func (q *Queries) GetGlobalConfigByVersion(ctx context.Context, arg GetGlobalConfigByVersionParams) (GlobalConfig, error) {
+	row := q.db.QueryRowContext(ctx, getGlobalConfigByVersion, arg.Namespace, arg.Version)
+	var i GlobalConfig
+	err := row.Scan(
+		&i.ID,
+		&i.Config,
+		&i.Version,
+		&i.CreatedAt,
+		&i.Namespace,
+		&i.Revision,
+		&i.Status,
+		&i.RolloutPercentage,
+		&i.RolloutSelector,
+		&i.EffectiveAt,
+	)
+	return i, err
+}
+
+const getLatestCanaryGlobalConfig = `-- name: GetLatestCanaryGlobalConfig :one
+SELECT id, config, version, created_at, namespace, revision, status, rollout_percentage, rollout_selector, effective_at 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1 AND status = 'canary' 
+ORDER BY 
+    version DESC LIMIT 1
+`
+
+func (q *Queries) GetLatestCanaryGlobalConfig(ctx context.Context, namespace string) (GlobalConfig, error) {
+	row := q.db.QueryRowContext(ctx, getLatestCanaryGlobalConfig, namespace)
+	var i GlobalConfig
+	err := row.Scan(
+		&i.ID,
+		&i.Config,
+		&i.Version,
+		&i.CreatedAt,
+		&i.Namespace,
+		&i.Revision,
+		&i.Status,
+		&i.RolloutPercentage,
+		&i.RolloutSelector,
+		&i.EffectiveAt,
 	)
 	return i, err
 }
 
 const getLatestVersionGlobalConfig = `-- name: GetLatestVersionGlobalConfig :one
-SELECT id, config, version, created_at 
+SELECT id, config, version, created_at, namespace, revision, status, rollout_percentage, rollout_selector, effective_at 
 FROM 
     global_config 
+WHERE 
+    namespace = $1 AND status = 'active' 
 ORDER BY 
     version DESC LIMIT 1
 `
 
-func (q *Queries) GetLatestVersionGlobalConfig(ctx context.Context) (GlobalConfig, error) {
-	row := q.db.QueryRowContext(ctx, getLatestVersionGlobalConfig)
+func (q *Queries) GetLatestVersionGlobalConfig(ctx context.Context, namespace string) (GlobalConfig, error) {
+	row := q.db.QueryRowContext(ctx, getLatestVersionGlobalConfig, namespace)
 	var i GlobalConfig
 	err := row.Scan(
 		&i.ID,
 		&i.Config,
 		&i.Version,
 		&i.CreatedAt,
+		&i.Namespace,
+		&i.Revision,
+		&i.Status,
+		&i.RolloutPercentage,
+		&i.RolloutSelector,
+		&i.EffectiveAt,
 	)
 	return i, err
 }
+
+const getMaxVersionGlobalConfig = `-- name: GetMaxVersionGlobalConfig :one
+SELECT COALESCE(MAX(version), 0)::BIGINT 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1
+`
+
+func (q *Queries) GetMaxVersionGlobalConfig(ctx context.Context, namespace string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getMaxVersionGlobalConfig, namespace)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getRevisionGlobalConfig = `-- name: GetRevisionGlobalConfig :one
+SELECT COALESCE(MAX(revision), 0)::BIGINT 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1
+`
+
+func (q *Queries) GetRevisionGlobalConfig(ctx context.Context, namespace string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getRevisionGlobalConfig, namespace)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const listAllGlobalConfigs = `-- name: ListAllGlobalConfigs :many
+SELECT id, config, version, created_at, namespace, revision, status, rollout_percentage, rollout_selector, effective_at 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1 
+ORDER BY 
+    version
+`
+
+func (q *Queries) ListAllGlobalConfigs(ctx context.Context, namespace string) ([]GlobalConfig, error) {
+	rows, err := q.db.QueryContext(ctx, listAllGlobalConfigs, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GlobalConfig
+	for rows.Next() {
+		var i GlobalConfig
+		if err := rows.Scan(
+			&i.ID,
+			&i.Config,
+			&i.Version,
+			&i.CreatedAt,
+			&i.Namespace,
+			&i.Revision,
+			&i.Status,
+			&i.RolloutPercentage,
+			&i.RolloutSelector,
+			&i.EffectiveAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGlobalConfigNamespaces = `-- name: ListGlobalConfigNamespaces :many
+SELECT namespace FROM global_config
+UNION
+SELECT namespace FROM config_schemas
+UNION
+SELECT namespace FROM scheduled_configs
+ORDER BY 
+    namespace
+`
+
+func (q *Queries) ListGlobalConfigNamespaces(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listGlobalConfigNamespaces)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var namespace string
+		if err := rows.Scan(&namespace); err != nil {
+			return nil, err
+		}
+		items = append(items, namespace)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGlobalConfigs = `-- name: ListGlobalConfigs :many
+SELECT id, config, version, created_at, namespace, revision, status, rollout_percentage, rollout_selector, effective_at 
+FROM 
+    global_config 
+WHERE 
+    namespace = $1 
+ORDER BY 
+    version DESC 
+LIMIT $2 OFFSET $3
+`
+
+type ListGlobalConfigsParams struct {
+	Namespace string
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) ListGlobalConfigs(ctx context.Context, arg ListGlobalConfigsParams) ([]GlobalConfig, error) {
+	rows, err := q.db.QueryContext(ctx, listGlobalConfigs, arg.Namespace, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GlobalConfig
+	for rows.Next() {
+		var i GlobalConfig
+		if err := rows.Scan(
+			&i.ID,
+			&i.Config,
+			&i.Version,
+			&i.CreatedAt,
+			&i.Namespace,
+			&i.Revision,
+			&i.Status,
+			&i.RolloutPercentage,
+			&i.RolloutSelector,
+			&i.EffectiveAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const notifyGlobalConfigUpdated = `-- name: NotifyGlobalConfigUpdated :exec
+SELECT pg_notify('global_config_updated', $1::text)
+`
+
+func (q *Queries) NotifyGlobalConfigUpdated(ctx context.Context, payload string) error {
+	_, err := q.db.ExecContext(ctx, notifyGlobalConfigUpdated, payload)
+	return err
+}
+
+const updateGlobalConfigRollout = `-- name: UpdateGlobalConfigRollout :execrows
+UPDATE global_config
+SET 
+    status = $3,
+    rollout_percentage = $4,
+    revision = nextval('config_revision_seq')
+WHERE 
+    namespace = $1 AND version = $2 AND status = 'canary'
+`
+
+type UpdateGlobalConfigRolloutParams struct {
+	Namespace         string
+	Version           int64
+	Status            string
+	RolloutPercentage int32
+}
+
+func (q *Queries) UpdateGlobalConfigRollout(ctx context.Context, arg UpdateGlobalConfigRolloutParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateGlobalConfigRollout,
+		arg.Namespace,
+		arg.Version,
+		arg.Status,
+		arg.RolloutPercentage,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
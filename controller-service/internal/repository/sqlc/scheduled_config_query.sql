@@ -0,0 +1,37 @@
+-- name: CreateScheduledConfig :one
+INSERT INTO scheduled_configs (namespace, config, rollout_percentage, rollout_selector, effective_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListScheduledConfigs :many
+SELECT * 
+FROM 
+    scheduled_configs 
+WHERE 
+    namespace = $1 
+ORDER BY 
+    effective_at, created_at;
+
+-- name: GetScheduledConfig :one
+SELECT * 
+FROM 
+    scheduled_configs 
+WHERE 
+    namespace = $1 AND id = $2;
+
+-- name: GetDueScheduledConfig :one
+SELECT * 
+FROM 
+    scheduled_configs 
+WHERE 
+    effective_at <= sqlc.arg(now)::timestamp 
+ORDER BY 
+    effective_at, created_at 
+LIMIT 1 
+FOR UPDATE SKIP LOCKED;
+
+-- name: DeleteScheduledConfig :one
+DELETE FROM scheduled_configs
+WHERE 
+    namespace = $1 AND id = $2
+RETURNING *;
@@ -0,0 +1,107 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (url, secret, namespaces, enabled)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * 
+FROM 
+    webhooks 
+WHERE 
+    id = $1;
+
+-- name: ListWebhooks :many
+SELECT * 
+FROM 
+    webhooks 
+ORDER BY 
+    created_at DESC;
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET 
+    url = $2,
+    namespaces = $3,
+    enabled = $4,
+    updated_at = now()
+WHERE 
+    id = $1
+RETURNING *;
+
+-- name: DeleteWebhook :one
+DELETE FROM webhooks
+WHERE 
+    id = $1
+RETURNING *;
+
+-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+SELECT 
+    id, sqlc.arg(event_id)::uuid, sqlc.arg(event_type)::text, sqlc.arg(payload)::jsonb
+FROM 
+    webhooks 
+WHERE 
+    enabled 
+    AND (cardinality(namespaces) = 0 OR sqlc.arg(namespace)::text = ANY(namespaces));
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET 
+    attempts = webhook_deliveries.attempts + 1,
+    next_attempt_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+FROM 
+    webhooks 
+WHERE 
+    webhook_deliveries.id IN (
+        SELECT 
+            pending.id 
+        FROM 
+            webhook_deliveries pending 
+        WHERE 
+            pending.status = 'pending' AND pending.next_attempt_at <= now() 
+        ORDER BY 
+            pending.next_attempt_at 
+        LIMIT sqlc.arg('limit') 
+        FOR UPDATE SKIP LOCKED
+    )
+    AND webhooks.id = webhook_deliveries.webhook_id
+RETURNING webhook_deliveries.id, webhook_deliveries.webhook_id, webhook_deliveries.event_id, webhook_deliveries.event_type, webhook_deliveries.payload, webhook_deliveries.attempts, webhooks.url, webhooks.secret, (webhook_deliveries.payload->>'namespace')::text AS namespace;
+
+-- name: MarkWebhookDeliveryDelivered :exec
+UPDATE webhook_deliveries
+SET 
+    status = 'delivered',
+    delivered_at = now(),
+    last_status_code = $2,
+    last_error = ''
+WHERE 
+    id = $1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET 
+    status = sqlc.arg(status),
+    next_attempt_at = now() + make_interval(secs => sqlc.arg(retry_after_seconds)::int),
+    last_status_code = sqlc.arg(last_status_code),
+    last_error = sqlc.arg(last_error)
+WHERE 
+    id = sqlc.arg(id);
+
+-- name: ListWebhookDeliveries :many
+SELECT * 
+FROM 
+    webhook_deliveries 
+WHERE 
+    webhook_id = sqlc.arg(webhook_id) 
+    AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)) 
+ORDER BY 
+    id DESC 
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountWebhookDeliveries :one
+SELECT COUNT(*) 
+FROM 
+    webhook_deliveries 
+WHERE 
+    webhook_id = sqlc.arg(webhook_id) 
+    AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status));
@@ -0,0 +1,28 @@
+// Package requestctx carries who made an API request, and from where, from
+// the HTTP middleware down to the service layer.
+package requestctx
+
+import "context"
+
+type Info struct {
+	// Actor names the API key the request was authenticated with.
+	Actor     string
+	SourceIP  string
+	RequestID string
+	// Certificate is the identity of the verified client certificate, if
+	// the caller presented one.
+	Certificate string
+}
+
+type contextKey struct{}
+
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the request info stored in ctx, or the zero Info for
+// work not started by an API request.
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(contextKey{}).(Info)
+	return info
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	queries "controller-service/internal/repository/sqlc"
+	"controller-service/internal/requestctx"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReregisterAgent creates or updates the agent with a caller-chosen ID, so an
+// agent keeps its identity across restarts. An existing agent can only be
+// re-registered by the identity that registered it, see callerIdentity.
+func (s *ControllerService) ReregisterAgent(ctx context.Context, agentID uuid.UUID, payload request.RegisterAgentRequest) (*response.ConfigResponse, error) {
+	namespace := payload.Namespace
+	if namespace == "" {
+		namespace = request.DefaultNamespace
+	}
+
+	config, err := s.registrationConfig(ctx, namespace)
+	if err != nil {
+		slog.Error("ReregisterAgent Failed to fetch global config", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	labels, err := marshalLabels(payload.Labels)
+	if err != nil {
+		slog.Error("ReregisterAgent Failed to marshal labels", slog.Any("error", err))
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("ReregisterAgent Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	identity := callerIdentity(ctx)
+
+	var previous *agentAuditState
+	existing, err := queryTx.GetAgent(ctx, agentID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		slog.Error("ReregisterAgent Failed to fetch agent", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+		return nil, err
+	case !ownsAgent(existing, identity):
+		slog.Warn("ReregisterAgent rejected agent of another identity", slog.String("agent_id", agentID.String()), slog.String("identity", identity))
+		return nil, ErrAgentForbidden
+	default:
+		state := toAgentAuditState(existing)
+		previous = &state
+	}
+
+	agentID, err = queryTx.UpsertAgent(ctx, queries.UpsertAgentParams{
+		ID:           agentID,
+		Name:         payload.Name,
+		Namespace:    namespace,
+		Labels:       labels,
+		RegisteredBy: identity,
+	})
+	// the upsert skips agents another identity registered in the meantime
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAgentForbidden
+	}
+	if err != nil {
+		slog.Error("ReregisterAgent Failed to upsert agent", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+		return nil, err
+	}
+
+	registered := agentAuditState{
+		ID:           agentID.String(),
+		Name:         payload.Name,
+		Namespace:    namespace,
+		Labels:       payload.Labels,
+		RegisteredBy: identity,
+	}
+	if err := recordAudit(ctx, queryTx, response.AuditActionAgentRegister, agentResource(agentID), previous, registered); err != nil {
+		slog.Error("ReregisterAgent Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("ReregisterAgent Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	resp, err := toConfigResponse(namespace, config)
+	if err != nil {
+		slog.Error("ReregisterAgent Failed to unmarshal global config", slog.Any("error", err))
+		return nil, err
+	}
+	resp.AgentID = agentID.String()
+
+	return resp, nil
+}
+
+// RecordHeartbeat records the liveness of an agent, reported by the identity
+// that registered it. The applied version and its applied_at time only
+// change when the agent reports a different version; 0, sent before the
+// agent applied any config, keeps the recorded one.
+func (s *ControllerService) RecordHeartbeat(ctx context.Context, agentID uuid.UUID, payload request.AgentHeartbeatRequest) error {
+	buildInfo, err := json.Marshal(payload.BuildInfo)
+	if err != nil {
+		slog.Error("RecordHeartbeat Failed to marshal build info", slog.Any("error", err))
+		return err
+	}
+	if payload.BuildInfo == nil {
+		buildInfo = []byte("{}")
+	}
+
+	updated, err := s.Repo.UpdateAgentHeartbeat(ctx, queries.UpdateAgentHeartbeatParams{
+		ID:             agentID,
+		AppliedVersion: sql.NullInt64{Int64: payload.AppliedVersion, Valid: payload.AppliedVersion > 0},
+		WorkerHealthy:  sql.NullBool{Bool: payload.WorkerHealthy, Valid: true},
+		BuildInfo:      buildInfo,
+		RegisteredBy:   callerIdentity(ctx),
+	})
+	if err != nil {
+		slog.Error("RecordHeartbeat Failed to update agent", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+		return err
+	}
+
+	if updated == 0 {
+		// the agent is unknown or bound to another identity
+		_, err := s.Repo.GetAgent(ctx, agentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			slog.Error("RecordHeartbeat Failed to fetch agent", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+			return err
+		}
+		return ErrAgentForbidden
+	}
+
+	return nil
+}
+
+// DeregisterAgent marks an agent shutting down as offline until it registers
+// or sends a heartbeat again. The agent and its config override are kept.
+// Only the identity that registered the agent may deregister it.
+func (s *ControllerService) DeregisterAgent(ctx context.Context, agentID uuid.UUID) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("DeregisterAgent Failed to begin transaction", slog.Any("error", err))
+		return err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	agent, err := queryTx.GetAgent(ctx, agentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		slog.Error("DeregisterAgent Failed to fetch agent", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+		return err
+	}
+	if !ownsAgent(agent, callerIdentity(ctx)) {
+		return ErrAgentForbidden
+	}
+
+	if err := queryTx.DeregisterAgent(ctx, agentID); err != nil {
+		slog.Error("DeregisterAgent Failed to update agent", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+		return err
+	}
+
+	state := toAgentAuditState(agent)
+	if err := recordAudit(ctx, queryTx, response.AuditActionAgentDeregister, agentResource(agentID), state, state); err != nil {
+		slog.Error("DeregisterAgent Failed to record audit event", slog.Any("error", err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("DeregisterAgent Failed to commit transaction", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// ListAgents lists the agents of a namespace, or every agent when namespace
+// is empty.
+func (s *ControllerService) ListAgents(ctx context.Context, namespace string) (*response.AgentListResponse, error) {
+	var (
+		agents []queries.Agent
+		err    error
+	)
+	if namespace == "" {
+		agents, err = s.Repo.ListAgents(ctx)
+	} else {
+		agents, err = s.Repo.ListAgentsByNamespace(ctx, namespace)
+	}
+	if err != nil {
+		slog.Error("ListAgents Failed to list agents", slog.Any("error", err))
+		return nil, err
+	}
+
+	items := make([]response.AgentResponse, 0, len(agents))
+	for _, agent := range agents {
+		items = append(items, s.toAgentResponse(agent))
+	}
+
+	return &response.AgentListResponse{
+		Items:             items,
+		StaleAfterSeconds: int(s.AgentStaleAfter.Seconds()),
+	}, nil
+}
+
+// callerIdentity names the caller an agent gets bound to when it registers:
+// the verified client certificate when there is one, as API keys may be
+// shared by many agents, otherwise the API key.
+func callerIdentity(ctx context.Context) string {
+	info := requestctx.FromContext(ctx)
+	if info.Certificate != "" {
+		return "cert:" + info.Certificate
+	}
+	return info.Actor
+}
+
+// ownsAgent reports whether identity may write to the agent. Agents
+// registered before identities were recorded are claimed by their next
+// registration.
+func ownsAgent(agent queries.Agent, identity string) bool {
+	return agent.RegisteredBy == "" || agent.RegisteredBy == identity
+}
+
+func marshalLabels(labels map[string]string) (json.RawMessage, error) {
+	if labels == nil {
+		return json.RawMessage("{}"), nil
+	}
+	return json.Marshal(labels)
+}
+
+func (s *ControllerService) toAgentResponse(agent queries.Agent) response.AgentResponse {
+	resp := response.AgentResponse{
+		ID:           agent.ID.String(),
+		Name:         agent.Name,
+		Namespace:    agent.Namespace,
+		Status:       s.agentStatus(agent),
+		RegisteredBy: agent.RegisteredBy,
+		CreatedAt:    agent.CreatedAt,
+	}
+
+	if agent.AppliedVersion.Valid {
+		resp.AppliedVersion = &agent.AppliedVersion.Int64
+	}
+	if agent.AppliedAt.Valid {
+		resp.AppliedAt = &agent.AppliedAt.Time
+	}
+	if agent.WorkerHealthy.Valid {
+		resp.WorkerHealthy = &agent.WorkerHealthy.Bool
+	}
+	if agent.LastSeenAt.Valid {
+		resp.LastSeenAt = &agent.LastSeenAt.Time
+	}
+	if agent.DeregisteredAt.Valid {
+		resp.DeregisteredAt = &agent.DeregisteredAt.Time
+	}
+	if err := json.Unmarshal(agent.BuildInfo, &resp.BuildInfo); err != nil {
+		slog.Warn("toAgentResponse Failed to unmarshal build info", slog.Any("error", err), slog.String("agent_id", agent.ID.String()))
+	}
+	if err := json.Unmarshal(agent.Labels, &resp.Labels); err != nil {
+		slog.Warn("toAgentResponse Failed to unmarshal labels", slog.Any("error", err), slog.String("agent_id", agent.ID.String()))
+	}
+
+	return resp
+}
+
+// agentStatus reports an agent as offline once it deregistered, as stale
+// when it has not sent a heartbeat within AgentStaleAfter, otherwise by the
+// worker health it last reported.
+func (s *ControllerService) agentStatus(agent queries.Agent) string {
+	if agent.DeregisteredAt.Valid {
+		return response.AgentStatusOffline
+	}
+	if !agent.LastSeenAt.Valid || time.Since(agent.LastSeenAt.Time) > s.AgentStaleAfter {
+		return response.AgentStatusStale
+	}
+	if !agent.WorkerHealthy.Bool {
+		return response.AgentStatusUnhealthy
+	}
+	return response.AgentStatusHealthy
+}
+
+// RunAgentReaper deletes agents that have not been seen for maxIdleDays, once
+// per interval, until ctx is done.
+func (s *ControllerService) RunAgentReaper(ctx context.Context, interval time.Duration, maxIdleDays int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.reapAgents(ctx, maxIdleDays)
+		if err != nil {
+			slog.Error("RunAgentReaper Failed to delete stale agents", slog.Any("error", err))
+		} else if deleted > 0 {
+			slog.Info("RunAgentReaper deleted stale agents", slog.Int("count", deleted), slog.Int("max_idle_days", maxIdleDays))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reapAgents deletes the agents not seen for maxIdleDays, recording an audit
+// event for each, and returns how many were deleted.
+func (s *ControllerService) reapAgents(ctx context.Context, maxIdleDays int) (int, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	deleted, err := queryTx.DeleteStaleAgents(ctx, int32(maxIdleDays))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, agent := range deleted {
+		if err := recordAudit(ctx, queryTx, response.AuditActionAgentDelete, agentResource(agent.ID), toAgentAuditState(agent), nil); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(deleted), nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/request"
+	mock_repository "controller-service/internal/repository/mocks"
+	queries "controller-service/internal/repository/sqlc"
+	"controller-service/internal/requestctx"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func TestRecordHeartbeatAppliedVersion(t *testing.T) {
+	agentID := uuid.New()
+
+	tests := []struct {
+		name           string
+		appliedVersion int64
+		updated        int64
+		getAgentErr    error
+		want           sql.NullInt64
+		wantErr        error
+	}{
+		{
+			name:           "applied version is recorded",
+			appliedVersion: 7,
+			updated:        1,
+			want:           sql.NullInt64{Int64: 7, Valid: true},
+		},
+		{
+			// a restarted agent reports 0 until its workers applied a config
+			// from the controller again, which must not reset applied_at
+			name:           "no applied version keeps the recorded one",
+			appliedVersion: 0,
+			updated:        1,
+			want:           sql.NullInt64{},
+		},
+		{
+			name:           "unknown agent",
+			appliedVersion: 7,
+			updated:        0,
+			getAgentErr:    sql.ErrNoRows,
+			want:           sql.NullInt64{Int64: 7, Valid: true},
+			wantErr:        ErrNotFound,
+		},
+		{
+			name:           "agent of another identity",
+			appliedVersion: 7,
+			updated:        0,
+			want:           sql.NullInt64{Int64: 7, Valid: true},
+			wantErr:        ErrAgentForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mock_repository.NewMockIRepository(ctrl)
+
+			repo.EXPECT().
+				UpdateAgentHeartbeat(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, arg queries.UpdateAgentHeartbeatParams) (int64, error) {
+					if arg.ID != agentID {
+						t.Errorf("ID = %s, want %s", arg.ID, agentID)
+					}
+					if arg.AppliedVersion != tt.want {
+						t.Errorf("AppliedVersion = %+v, want %+v", arg.AppliedVersion, tt.want)
+					}
+					if arg.RegisteredBy != "api-key:agents" {
+						t.Errorf("RegisteredBy = %q, want %q", arg.RegisteredBy, "api-key:agents")
+					}
+					return tt.updated, nil
+				})
+			if tt.updated == 0 {
+				repo.EXPECT().
+					GetAgent(gomock.Any(), agentID).
+					Return(queries.Agent{ID: agentID, RegisteredBy: "api-key:other"}, tt.getAgentErr)
+			}
+
+			ctx := requestctx.WithInfo(context.Background(), requestctx.Info{Actor: "api-key:agents"})
+			s := &ControllerService{Repo: repo}
+			err := s.RecordHeartbeat(ctx, agentID, request.AgentHeartbeatRequest{
+				AppliedVersion: tt.appliedVersion,
+				WorkerHealthy:  true,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RecordHeartbeat() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOwnsAgent(t *testing.T) {
+	tests := []struct {
+		name         string
+		registeredBy string
+		info         requestctx.Info
+		want         bool
+	}{
+		{
+			name:         "same api key",
+			registeredBy: "api-key:agents",
+			info:         requestctx.Info{Actor: "api-key:agents"},
+			want:         true,
+		},
+		{
+			name:         "other api key",
+			registeredBy: "api-key:agents",
+			info:         requestctx.Info{Actor: "api-key:other"},
+			want:         false,
+		},
+		{
+			name:         "same certificate with another api key",
+			registeredBy: "cert:agent-1",
+			info:         requestctx.Info{Actor: "api-key:other", Certificate: "agent-1"},
+			want:         true,
+		},
+		{
+			// agents sharing a key are told apart by their certificates
+			name:         "other certificate with the same api key",
+			registeredBy: "cert:agent-1",
+			info:         requestctx.Info{Actor: "api-key:agents", Certificate: "agent-2"},
+			want:         false,
+		},
+		{
+			name:         "agent registered before identities were recorded",
+			registeredBy: "",
+			info:         requestctx.Info{Actor: "api-key:other"},
+			want:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := requestctx.WithInfo(context.Background(), tt.info)
+			agent := queries.Agent{ID: uuid.New(), RegisteredBy: tt.registeredBy}
+
+			if got := ownsAgent(agent, callerIdentity(ctx)); got != tt.want {
+				t.Errorf("ownsAgent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	queries "controller-service/internal/repository/sqlc"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefix marks keys issued by the controller, so leaked keys are easy
+// to recognise in logs and secret scanners.
+const apiKeyPrefix = "dcm_"
+
+// apiKeyActorPrefix starts the audit actor of requests authenticated with an
+// API key, followed by the key's name.
+const apiKeyActorPrefix = "api-key:"
+
+// CreateAPIKey issues a new random API key. Only its SHA-256 hash is stored;
+// the plaintext key is in the response and cannot be retrieved later.
+func (s *ControllerService) CreateAPIKey(ctx context.Context, payload request.CreateAPIKeyRequest) (*response.CreatedAPIKeyResponse, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		slog.Error("CreateAPIKey Failed to generate key", slog.Any("error", err))
+		return nil, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	var expiresAt sql.NullTime
+	if payload.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: payload.ExpiresAt.UTC(), Valid: true}
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("CreateAPIKey Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	apiKey, err := queryTx.CreateAPIKey(ctx, queries.CreateAPIKeyParams{
+		Name:      payload.Name,
+		KeyHash:   hashAPIKey(key),
+		KeyPrefix: key[:len(apiKeyPrefix)+6],
+		Scopes:    payload.Scopes,
+		ExpiresAt: expiresAt,
+		CreatedBy: auditActor(ctx),
+	})
+	if isUniqueViolation(err) {
+		return nil, ErrAPIKeyExists
+	}
+	if err != nil {
+		slog.Error("CreateAPIKey Failed to create api key", slog.Any("error", err))
+		return nil, err
+	}
+
+	resp := toAPIKeyResponse(apiKey)
+	if err := recordAudit(ctx, queryTx, response.AuditActionAPIKeyCreate, apiKeyResource(apiKey.ID), nil, resp); err != nil {
+		slog.Error("CreateAPIKey Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("CreateAPIKey Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &response.CreatedAPIKeyResponse{
+		APIKeyResponse: resp,
+		Key:            key,
+	}, nil
+}
+
+func (s *ControllerService) ListAPIKeys(ctx context.Context) (*response.APIKeyListResponse, error) {
+	apiKeys, err := s.Repo.ListAPIKeys(ctx)
+	if err != nil {
+		slog.Error("ListAPIKeys Failed to list api keys", slog.Any("error", err))
+		return nil, err
+	}
+
+	items := make([]response.APIKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		items = append(items, toAPIKeyResponse(apiKey))
+	}
+
+	return &response.APIKeyListResponse{Items: items}, nil
+}
+
+// RevokeAPIKey disables a key for good. Revoking a revoked key keeps its
+// original revocation time.
+func (s *ControllerService) RevokeAPIKey(ctx context.Context, id uuid.UUID) (*response.APIKeyResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("RevokeAPIKey Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	apiKey, err := queryTx.RevokeAPIKey(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("RevokeAPIKey Failed to revoke api key", slog.Any("error", err), slog.String("api_key_id", id.String()))
+		return nil, err
+	}
+
+	resp := toAPIKeyResponse(apiKey)
+	previous := resp
+	previous.RevokedAt = nil
+	if err := recordAudit(ctx, queryTx, response.AuditActionAPIKeyRevoke, apiKeyResource(apiKey.ID), previous, resp); err != nil {
+		slog.Error("RevokeAPIKey Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("RevokeAPIKey Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// AuthenticateAPIKey resolves a plaintext key to the key it was issued as.
+// Unknown, revoked and expired keys return ErrNotFound.
+func (s *ControllerService) AuthenticateAPIKey(ctx context.Context, key string) (*response.APIKeyResponse, error) {
+	apiKey, err := s.Repo.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("AuthenticateAPIKey Failed to fetch api key", slog.Any("error", err))
+		return nil, err
+	}
+
+	if apiKey.RevokedAt.Valid {
+		return nil, ErrNotFound
+	}
+	if apiKey.ExpiresAt.Valid && !time.Now().Before(apiKey.ExpiresAt.Time) {
+		return nil, ErrNotFound
+	}
+
+	resp := toAPIKeyResponse(apiKey)
+	return &resp, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func apiKeyResource(id uuid.UUID) string {
+	return "api-keys/" + id.String()
+}
+
+func toAPIKeyResponse(apiKey queries.ApiKey) response.APIKeyResponse {
+	resp := response.APIKeyResponse{
+		ID:        apiKey.ID.String(),
+		Name:      apiKey.Name,
+		KeyPrefix: apiKey.KeyPrefix,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: apiKey.CreatedAt,
+		CreatedBy: apiKey.CreatedBy,
+	}
+	if apiKey.ExpiresAt.Valid {
+		resp.ExpiresAt = &apiKey.ExpiresAt.Time
+	}
+	if apiKey.RevokedAt.Valid {
+		resp.RevokedAt = &apiKey.RevokedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	"controller-service/internal/repository"
+	queries "controller-service/internal/repository/sqlc"
+	"controller-service/internal/requestctx"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// auditSystemActor records changes made by the controller itself, such as
+// the agent reaper.
+const auditSystemActor = "system"
+
+// auditExportBatchSize is how many audit events ExportAuditEvents reads per
+// query.
+const auditExportBatchSize = 500
+
+// agentAuditState is what an audit event records of an agent.
+type agentAuditState struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Namespace    string            `json:"namespace"`
+	Labels       map[string]string `json:"labels"`
+	RegisteredBy string            `json:"registered_by,omitempty"`
+}
+
+// recordAudit stores an audit event for a change made through repo, which
+// should be the transaction making the change so both commit together. The
+// actor, source IP and request ID are taken from ctx; before and after are
+// marshaled to JSON, nil meaning the resource did not exist.
+func recordAudit(ctx context.Context, repo repository.IRepository, action, resource string, before, after any) error {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	info := requestctx.FromContext(ctx)
+
+	return repo.CreateAuditEvent(ctx, queries.CreateAuditEventParams{
+		Actor:     auditActor(ctx),
+		Action:    action,
+		Resource:  resource,
+		SourceIp:  info.SourceIP,
+		RequestID: info.RequestID,
+		Before:    beforeJSON,
+		After:     afterJSON,
+	})
+}
+
+// auditActor is who ctx acts for: the authenticated key of a request, or the
+// controller itself.
+func auditActor(ctx context.Context) string {
+	if actor := requestctx.FromContext(ctx).Actor; actor != "" {
+		return actor
+	}
+	return auditSystemActor
+}
+
+func (s *ControllerService) ListAuditEvents(ctx context.Context, filter request.AuditFilter, pagination request.PaginationRequest) (*response.AuditEventListResponse, error) {
+	actor, action, since, until := auditFilterParams(filter)
+
+	events, err := s.Repo.ListAuditEvents(ctx, queries.ListAuditEventsParams{
+		Actor:  actor,
+		Action: action,
+		Since:  since,
+		Until:  until,
+		Limit:  int32(pagination.PageSize),
+		Offset: int32(pagination.Offset()),
+	})
+	if err != nil {
+		slog.Error("ListAuditEvents Failed to list audit events", slog.Any("error", err))
+		return nil, err
+	}
+
+	total, err := s.Repo.CountAuditEvents(ctx, queries.CountAuditEventsParams{
+		Actor:  actor,
+		Action: action,
+		Since:  since,
+		Until:  until,
+	})
+	if err != nil {
+		slog.Error("ListAuditEvents Failed to count audit events", slog.Any("error", err))
+		return nil, err
+	}
+
+	items := make([]response.AuditEventResponse, 0, len(events))
+	for _, event := range events {
+		items = append(items, toAuditEventResponse(event))
+	}
+
+	return &response.AuditEventListResponse{
+		Items:    items,
+		Page:     pagination.Page,
+		PageSize: pagination.PageSize,
+		Total:    total,
+	}, nil
+}
+
+// ExportAuditEvents passes every audit event matching filter to emit, oldest
+// first, reading them in batches so an export of the whole log does not
+// have to fit in memory. It stops at the first error returned by emit.
+func (s *ControllerService) ExportAuditEvents(ctx context.Context, filter request.AuditFilter, emit func(response.AuditEventResponse) error) error {
+	actor, action, since, until := auditFilterParams(filter)
+
+	var afterID int64
+	for {
+		events, err := s.Repo.ListAuditEventsAfter(ctx, queries.ListAuditEventsAfterParams{
+			AfterID: afterID,
+			Actor:   actor,
+			Action:  action,
+			Since:   since,
+			Until:   until,
+			Limit:   auditExportBatchSize,
+		})
+		if err != nil {
+			slog.Error("ExportAuditEvents Failed to list audit events", slog.Any("error", err), slog.Int64("after_id", afterID))
+			return err
+		}
+
+		for _, event := range events {
+			if err := emit(toAuditEventResponse(event)); err != nil {
+				return err
+			}
+			afterID = event.ID
+		}
+
+		if len(events) < auditExportBatchSize {
+			return nil
+		}
+	}
+}
+
+func auditFilterParams(filter request.AuditFilter) (actor, action sql.NullString, since, until sql.NullTime) {
+	actor = sql.NullString{String: filter.Actor, Valid: filter.Actor != ""}
+	action = sql.NullString{String: filter.Action, Valid: filter.Action != ""}
+	since = sql.NullTime{Time: filter.Since, Valid: !filter.Since.IsZero()}
+	until = sql.NullTime{Time: filter.Until, Valid: !filter.Until.IsZero()}
+	return actor, action, since, until
+}
+
+func toAuditEventResponse(event queries.AuditEvent) response.AuditEventResponse {
+	return response.AuditEventResponse{
+		ID:        event.ID,
+		Actor:     event.Actor,
+		Action:    event.Action,
+		Resource:  event.Resource,
+		SourceIP:  event.SourceIp,
+		RequestID: event.RequestID,
+		Before:    event.Before,
+		After:     event.After,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+func configResource(namespace string) string {
+	return fmt.Sprintf("namespaces/%s/config", namespace)
+}
+
+func agentResource(agentID uuid.UUID) string {
+	return "agents/" + agentID.String()
+}
+
+// configAuditState is what an audit event records of a config version; nil
+// when the namespace had none.
+func configAuditState(config *queries.GlobalConfig) *response.ConfigVersionResponse {
+	if config == nil {
+		return nil
+	}
+	resp := toConfigVersionResponse(*config)
+	return &resp
+}
+
+func toAgentAuditState(agent queries.Agent) agentAuditState {
+	state := agentAuditState{
+		ID:           agent.ID.String(),
+		Name:         agent.Name,
+		Namespace:    agent.Namespace,
+		RegisteredBy: agent.RegisteredBy,
+	}
+	if err := json.Unmarshal(agent.Labels, &state.Labels); err != nil {
+		slog.Warn("toAgentAuditState Failed to unmarshal labels", slog.Any("error", err), slog.String("agent_id", agent.ID.String()))
+	}
+	return state
+}
+
+// currentGlobalConfig returns the latest version of the namespace that was
+// not aborted, which may be a canary, or nil when there is none.
+func currentGlobalConfig(ctx context.Context, repo repository.IRepository, namespace string) (*queries.GlobalConfig, error) {
+	version, err := repo.GetCurrentVersionGlobalConfig(ctx, namespace)
+	if err != nil || version == 0 {
+		return nil, err
+	}
+
+	config, err := repo.GetGlobalConfigByVersion(ctx, queries.GetGlobalConfigByVersionParams{
+		Namespace: namespace,
+		Version:   version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	queries "controller-service/internal/repository/sqlc"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// ExportConfig returns every namespace with its schema, its latest active
+// document and its whole version history.
+func (s *ControllerService) ExportConfig(ctx context.Context) (*response.ConfigBundle, error) {
+	namespaces, err := s.Repo.ListGlobalConfigNamespaces(ctx)
+	if err != nil {
+		slog.Error("ExportConfig Failed to list namespaces", slog.Any("error", err))
+		return nil, err
+	}
+
+	bundle := &response.ConfigBundle{
+		ExportedAt: time.Now().UTC(),
+		Namespaces: make([]response.ConfigBundleNamespace, 0, len(namespaces)),
+	}
+	for _, namespace := range namespaces {
+		entry := response.ConfigBundleNamespace{Namespace: namespace}
+
+		schema, err := s.Repo.GetConfigSchema(ctx, namespace)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			slog.Error("ExportConfig Failed to fetch config schema", slog.Any("error", err), slog.String("namespace", namespace))
+			return nil, err
+		default:
+			entry.Schema = schema.Schema
+		}
+
+		configs, err := s.Repo.ListAllGlobalConfigs(ctx, namespace)
+		if err != nil {
+			slog.Error("ExportConfig Failed to list global configs", slog.Any("error", err), slog.String("namespace", namespace))
+			return nil, err
+		}
+
+		entry.Versions = make([]response.ConfigVersionResponse, 0, len(configs))
+		for _, config := range configs {
+			if config.Status == response.ConfigStatusActive {
+				entry.Config = config.Config
+				entry.Version = config.Version
+			}
+			entry.Versions = append(entry.Versions, toConfigVersionResponse(config))
+		}
+
+		bundle.Namespaces = append(bundle.Namespaces, entry)
+	}
+
+	return bundle, nil
+}
+
+// ImportConfig brings every namespace of the bundle to the state it
+// describes, in a single transaction. Schemas and documents equal to the
+// current ones are left alone, so importing the same bundle twice changes
+// nothing. Documents go through the same checks as UpdateConfig; they and
+// schemas become proposals in namespaces requiring approval.
+func (s *ControllerService) ImportConfig(ctx context.Context, payload request.ConfigImportRequest) (*response.ConfigImportResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("ImportConfig Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	items := make([]response.ConfigImportResult, 0, len(payload.Namespaces))
+	for _, entry := range payload.Namespaces {
+		result, err := s.importNamespace(ctx, queryTx, entry)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, result)
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("ImportConfig Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &response.ConfigImportResponse{Items: items}, nil
+}
+
+// importNamespace applies the schema of entry before its document, so the
+// document is validated against the schema it comes with. A schema that is
+// only proposed is not in force yet, so the document is checked against the
+// current one.
+func (s *ControllerService) importNamespace(ctx context.Context, queryTx *queries.Queries, entry request.ConfigImportNamespace) (response.ConfigImportResult, error) {
+	namespace := entry.Namespace
+	result := response.ConfigImportResult{Namespace: namespace}
+
+	if entry.Schema != nil {
+		result.Schema = response.ConfigImportUpdated
+
+		current, err := queryTx.GetConfigSchema(ctx, namespace)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			slog.Error("importNamespace Failed to fetch config schema", slog.Any("error", err), slog.String("namespace", namespace))
+			return result, err
+		default:
+			unchanged, err := sameDocument(current.Schema, entry.Schema)
+			if err != nil {
+				slog.Error("importNamespace Failed to compare config schema", slog.Any("error", err))
+				return result, err
+			}
+			if unchanged {
+				result.Schema = response.ConfigImportUnchanged
+			}
+		}
+
+		switch {
+		case result.Schema == response.ConfigImportUnchanged:
+		case s.approvalRequired(namespace):
+			proposalID, err := s.proposeImportedSchema(ctx, queryTx, namespace, entry.Schema)
+			if err != nil {
+				return result, err
+			}
+			result.Schema = response.ConfigImportProposed
+			result.SchemaProposalID = proposalID
+		default:
+			if _, err := setConfigSchema(ctx, queryTx, namespace, entry.Schema); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if entry.Config == nil {
+		return result, nil
+	}
+
+	latestGlobalConfig, err := queryTx.GetLatestVersionGlobalConfig(ctx, namespace)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		slog.Error("importNamespace Failed to fetch global config", slog.Any("error", err), slog.String("namespace", namespace))
+		return result, err
+	default:
+		// compared with the active version, so a canary rolling out is not
+		// aborted by re-importing the document it replaces
+		unchanged, err := sameDocument(latestGlobalConfig.Config, entry.Config)
+		if err != nil {
+			slog.Error("importNamespace Failed to compare global config", slog.Any("error", err))
+			return result, err
+		}
+		if unchanged {
+			result.Config = response.ConfigImportUnchanged
+			result.Version = latestGlobalConfig.Version
+			return result, nil
+		}
+	}
+
+	if s.approvalRequired(namespace) {
+		proposal, err := pendingConfigProposal(ctx, queryTx, namespace, entry.Config)
+		if err != nil {
+			return result, err
+		}
+		if proposal != nil {
+			result.Config = response.ConfigImportProposed
+			result.ProposalID = proposal.ID.String()
+			return result, nil
+		}
+	}
+
+	resp, err := s.submitConfig(ctx, queryTx, namespace, request.UpdateConfigRequest{Config: entry.Config})
+	if err != nil {
+		return result, err
+	}
+
+	result.Config = response.ConfigImportUpdated
+	result.Version = resp.Version
+	if resp.Proposal != nil {
+		result.Config = response.ConfigImportProposed
+		result.ProposalID = resp.Proposal.ID
+	}
+	return result, nil
+}
+
+// proposeImportedSchema proposes schema for the namespace and returns the
+// proposal's ID, reusing a pending proposal of the same schema so importing
+// the same bundle twice proposes it once.
+func (s *ControllerService) proposeImportedSchema(ctx context.Context, queryTx *queries.Queries, namespace string, schema json.RawMessage) (string, error) {
+	proposals, err := queryTx.ListConfigProposals(ctx, queries.ListConfigProposalsParams{
+		Namespace: namespace,
+		Status:    sql.NullString{String: response.ConfigProposalStatusPending, Valid: true},
+	})
+	if err != nil {
+		slog.Error("proposeImportedSchema Failed to list config proposals", slog.Any("error", err), slog.String("namespace", namespace))
+		return "", err
+	}
+
+	for _, proposal := range proposals {
+		if proposal.Kind != response.ConfigProposalKindSchema || !time.Now().Before(proposal.ExpiresAt) {
+			continue
+		}
+
+		var payload schemaProposal
+		if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+			slog.Error("proposeImportedSchema Failed to unmarshal schema request", slog.Any("error", err), slog.String("id", proposal.ID.String()))
+			return "", err
+		}
+
+		same, err := sameDocument(payload.Schema, schema)
+		if err != nil {
+			slog.Error("proposeImportedSchema Failed to compare proposal schema", slog.Any("error", err))
+			return "", err
+		}
+		if same {
+			return proposal.ID.String(), nil
+		}
+	}
+
+	resp, err := s.proposeConfigSchema(ctx, queryTx, namespace, schema)
+	if err != nil {
+		return "", err
+	}
+	return resp.Proposal.ID, nil
+}
+
+// pendingConfigProposal returns the pending proposal of the namespace for
+// the given document, if there is one.
+func pendingConfigProposal(ctx context.Context, queryTx *queries.Queries, namespace string, config json.RawMessage) (*queries.ConfigProposal, error) {
+	proposals, err := queryTx.ListConfigProposals(ctx, queries.ListConfigProposalsParams{
+		Namespace: namespace,
+		Status:    sql.NullString{String: response.ConfigProposalStatusPending, Valid: true},
+	})
+	if err != nil {
+		slog.Error("pendingConfigProposal Failed to list config proposals", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	for _, proposal := range proposals {
+		if proposal.Kind != response.ConfigProposalKindUpdate {
+			continue
+		}
+
+		var payload request.UpdateConfigRequest
+		if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+			slog.Error("pendingConfigProposal Failed to unmarshal update request", slog.Any("error", err), slog.String("id", proposal.ID.String()))
+			return nil, err
+		}
+		if payload.Rollout != nil || payload.EffectiveAt != nil || !time.Now().Before(proposal.ExpiresAt) {
+			continue
+		}
+
+		same, err := sameDocument(payload.Config, config)
+		if err != nil {
+			slog.Error("pendingConfigProposal Failed to compare proposal config", slog.Any("error", err))
+			return nil, err
+		}
+		if same {
+			return &proposal, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	queries "controller-service/internal/repository/sqlc"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"sort"
+)
+
+const (
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeChanged = "changed"
+)
+
+func (s *ControllerService) ListConfigVersions(ctx context.Context, namespace string, pagination request.PaginationRequest) (*response.ConfigVersionListResponse, error) {
+	configs, err := s.Repo.ListGlobalConfigs(ctx, queries.ListGlobalConfigsParams{
+		Namespace: namespace,
+		Limit:     int32(pagination.PageSize),
+		Offset:    int32(pagination.Offset()),
+	})
+	if err != nil {
+		slog.Error("ListConfigVersions Failed to list global configs", slog.Any("error", err))
+		return nil, err
+	}
+
+	total, err := s.Repo.CountGlobalConfigs(ctx, namespace)
+	if err != nil {
+		slog.Error("ListConfigVersions Failed to count global configs", slog.Any("error", err))
+		return nil, err
+	}
+
+	items := make([]response.ConfigVersionResponse, 0, len(configs))
+	for _, config := range configs {
+		items = append(items, toConfigVersionResponse(config))
+	}
+
+	return &response.ConfigVersionListResponse{
+		Items:    items,
+		Page:     pagination.Page,
+		PageSize: pagination.PageSize,
+		Total:    total,
+	}, nil
+}
+
+func (s *ControllerService) GetConfigVersion(ctx context.Context, namespace string, version int64) (*response.ConfigVersionResponse, error) {
+	config, err := s.Repo.GetGlobalConfigByVersion(ctx, queries.GetGlobalConfigByVersionParams{
+		Namespace: namespace,
+		Version:   version,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("GetConfigVersion Failed to fetch global config", slog.Any("error", err), slog.Int64("version", version))
+		return nil, err
+	}
+
+	resp := toConfigVersionResponse(config)
+	return &resp, nil
+}
+
+func (s *ControllerService) DiffConfigVersions(ctx context.Context, namespace string, from, to int64) (*response.ConfigDiffResponse, error) {
+	fromConfig, err := s.Repo.GetGlobalConfigByVersion(ctx, queries.GetGlobalConfigByVersionParams{
+		Namespace: namespace,
+		Version:   from,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("DiffConfigVersions Failed to fetch from global config", slog.Any("error", err), slog.Int64("version", from))
+		return nil, err
+	}
+
+	toConfig, err := s.Repo.GetGlobalConfigByVersion(ctx, queries.GetGlobalConfigByVersionParams{
+		Namespace: namespace,
+		Version:   to,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("DiffConfigVersions Failed to fetch to global config", slog.Any("error", err), slog.Int64("version", to))
+		return nil, err
+	}
+
+	changes, err := diffConfig(fromConfig.Config, toConfig.Config)
+	if err != nil {
+		slog.Error("DiffConfigVersions Failed to diff global configs", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &response.ConfigDiffResponse{
+		From:    from,
+		To:      to,
+		Changes: changes,
+	}, nil
+}
+
+// RollbackConfig creates a new active version whose content is a copy of the
+// given version and aborts any canary rollout. No version is written when the
+// latest active version already has that content. In namespaces requiring
+// approval the rollback is stored as a proposal instead.
+func (s *ControllerService) RollbackConfig(ctx context.Context, namespace string, version int64) (*response.ConfigVersionResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("RollbackConfig Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	var resp *response.ConfigVersionResponse
+	if s.approvalRequired(namespace) {
+		resp, err = s.proposeRollback(ctx, queryTx, namespace, version)
+	} else {
+		resp, err = s.rollbackConfig(ctx, queryTx, namespace, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("RollbackConfig Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// rollbackTarget returns the version a rollback copies.
+func rollbackTarget(ctx context.Context, queryTx *queries.Queries, namespace string, version int64) (*queries.GlobalConfig, error) {
+	targetGlobalConfig, err := queryTx.GetGlobalConfigByVersion(ctx, queries.GetGlobalConfigByVersionParams{
+		Namespace: namespace,
+		Version:   version,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("rollbackTarget Failed to fetch target global config", slog.Any("error", err), slog.Int64("version", version))
+		return nil, err
+	}
+	return &targetGlobalConfig, nil
+}
+
+// rollbackConfig writes a rollback through queryTx, see RollbackConfig.
+func (s *ControllerService) rollbackConfig(ctx context.Context, queryTx *queries.Queries, namespace string, version int64) (*response.ConfigVersionResponse, error) {
+	targetGlobalConfig, err := rollbackTarget(ctx, queryTx, namespace, version)
+	if err != nil {
+		return nil, err
+	}
+
+	previousGlobalConfig, err := currentGlobalConfig(ctx, queryTx, namespace)
+	if err != nil {
+		slog.Error("rollbackConfig Failed to fetch current global config", slog.Any("error", err))
+		return nil, err
+	}
+
+	// a rollback supersedes any canary still rolling out
+	aborted, err := queryTx.AbortCanaryGlobalConfigs(ctx, namespace)
+	if err != nil {
+		slog.Error("rollbackConfig Failed to abort canary global configs", slog.Any("error", err))
+		return nil, err
+	}
+
+	latestGlobalConfig, err := queryTx.GetLatestVersionGlobalConfig(ctx, namespace)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		slog.Error("rollbackConfig Failed to fetch global config", slog.Any("error", err))
+		return nil, err
+	case bytes.Equal(latestGlobalConfig.Config, targetGlobalConfig.Config):
+		slog.Info("rollbackConfig config is already up to date", slog.Int64("version", latestGlobalConfig.Version))
+		if aborted > 0 {
+			// canary agents go back to the active version
+			if err := recordAudit(ctx, queryTx, response.AuditActionConfigRollback, configResource(namespace), configAuditState(previousGlobalConfig), configAuditState(&latestGlobalConfig)); err != nil {
+				slog.Error("rollbackConfig Failed to record audit event", slog.Any("error", err))
+				return nil, err
+			}
+			if err := enqueueAbortEvent(ctx, queryTx, &latestGlobalConfig, previousGlobalConfig); err != nil {
+				slog.Error("rollbackConfig Failed to enqueue webhook event", slog.Any("error", err))
+				return nil, err
+			}
+			if err := queryTx.NotifyGlobalConfigUpdated(ctx, namespace); err != nil {
+				slog.Error("rollbackConfig Failed to notify global config update", slog.Any("error", err))
+				return nil, err
+			}
+		}
+		resp := toConfigVersionResponse(latestGlobalConfig)
+		return &resp, nil
+	}
+
+	latestVersion, err := queryTx.GetMaxVersionGlobalConfig(ctx, namespace)
+	if err != nil {
+		slog.Error("rollbackConfig Failed to fetch latest version", slog.Any("error", err))
+		return nil, err
+	}
+
+	newGlobalConfig, err := queryTx.CreateGlobalConfig(ctx, queries.CreateGlobalConfigParams{
+		Namespace:         namespace,
+		Config:            targetGlobalConfig.Config,
+		Version:           latestVersion + 1,
+		Status:            response.ConfigStatusActive,
+		RolloutPercentage: 100,
+		RolloutSelector:   json.RawMessage("{}"),
+	})
+	if isUniqueViolation(err) {
+		return nil, s.versionConflict(ctx, namespace)
+	}
+	if err != nil {
+		slog.Error("rollbackConfig Failed to create global config", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, queryTx, response.AuditActionConfigRollback, configResource(namespace), configAuditState(previousGlobalConfig), configAuditState(&newGlobalConfig)); err != nil {
+		slog.Error("rollbackConfig Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := enqueueConfigEvent(ctx, queryTx, response.AuditActionConfigRollback, previousGlobalConfig, newGlobalConfig); err != nil {
+		slog.Error("rollbackConfig Failed to enqueue webhook event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := queryTx.NotifyGlobalConfigUpdated(ctx, namespace); err != nil {
+		slog.Error("rollbackConfig Failed to notify global config update", slog.Any("error", err))
+		return nil, err
+	}
+
+	slog.Info("rollbackConfig rolled back global config", slog.String("namespace", namespace), slog.Int64("from_version", version), slog.Int64("new_version", newGlobalConfig.Version))
+
+	resp := toConfigVersionResponse(newGlobalConfig)
+	return &resp, nil
+}
+
+func toConfigVersionResponse(config queries.GlobalConfig) response.ConfigVersionResponse {
+	resp := response.ConfigVersionResponse{
+		Namespace: config.Namespace,
+		Version:   config.Version,
+		Status:    config.Status,
+		Config:    config.Config,
+		CreatedAt: config.CreatedAt,
+	}
+	if config.EffectiveAt.Valid {
+		resp.EffectiveAt = &config.EffectiveAt.Time
+	}
+
+	if config.Status != response.ConfigStatusActive && rolloutStatus(config.RolloutPercentage, config.RolloutSelector) == response.ConfigStatusCanary {
+		resp.Rollout = &response.RolloutPolicy{Percentage: int(config.RolloutPercentage)}
+		if err := json.Unmarshal(config.RolloutSelector, &resp.Rollout.Selector); err != nil {
+			slog.Warn("toConfigVersionResponse Failed to unmarshal rollout selector", slog.Any("error", err), slog.Int64("version", config.Version))
+		}
+	}
+
+	return resp
+}
+
+// sameDocument reports whether two JSON documents are equal regardless of
+// key order and formatting.
+func sameDocument(a, b json.RawMessage) (bool, error) {
+	aValue, err := decodeDocument(a)
+	if err != nil {
+		return false, err
+	}
+	bValue, err := decodeDocument(b)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(aValue, bValue), nil
+}
+
+// diffConfig compares two JSON documents and returns one change per leaf
+// field, using dot-separated paths for nested objects.
+func diffConfig(from, to json.RawMessage) ([]response.ConfigFieldChange, error) {
+	var fromValue, toValue any
+	if err := json.Unmarshal(from, &fromValue); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &toValue); err != nil {
+		return nil, err
+	}
+
+	changes := []response.ConfigFieldChange{}
+	diffValue("", fromValue, toValue, &changes)
+	return changes, nil
+}
+
+func diffValue(path string, from, to any, changes *[]response.ConfigFieldChange) {
+	fromObject, fromIsObject := from.(map[string]any)
+	toObject, toIsObject := to.(map[string]any)
+
+	if fromIsObject && toIsObject {
+		keys := make(map[string]struct{}, len(fromObject)+len(toObject))
+		for key := range fromObject {
+			keys[key] = struct{}{}
+		}
+		for key := range toObject {
+			keys[key] = struct{}{}
+		}
+
+		sortedKeys := make([]string, 0, len(keys))
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+
+		for _, key := range sortedKeys {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+
+			fromField, inFrom := fromObject[key]
+			toField, inTo := toObject[key]
+			switch {
+			case !inFrom:
+				*changes = append(*changes, response.ConfigFieldChange{Field: field, Type: changeAdded, To: toField})
+			case !inTo:
+				*changes = append(*changes, response.ConfigFieldChange{Field: field, Type: changeRemoved, From: fromField})
+			default:
+				diffValue(field, fromField, toField, changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, response.ConfigFieldChange{Field: path, Type: changeChanged, From: from, To: to})
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	queries "controller-service/internal/repository/sqlc"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+func (s *ControllerService) GetAgentConfigOverride(ctx context.Context, agentID uuid.UUID) (*response.AgentConfigOverrideResponse, error) {
+	override, err := s.Repo.GetAgentConfigOverride(ctx, agentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("GetAgentConfigOverride Failed to fetch agent config override", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+		return nil, err
+	}
+
+	return toAgentConfigOverrideResponse(override), nil
+}
+
+// SetAgentConfigOverride replaces the config override of an agent. An empty
+// override clears it; the row is kept so the override version never goes
+// backwards. Either way the agent takes a new config revision. The override applied
+// to the latest config of the agent's namespace must still pass validation.
+// In namespaces requiring approval the change is stored as a proposal
+// instead.
+func (s *ControllerService) SetAgentConfigOverride(ctx context.Context, agentID uuid.UUID, payload request.AgentConfigOverrideRequest) (*response.AgentConfigOverrideResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("SetAgentConfigOverride Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	agent, err := queryTx.GetAgent(ctx, agentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("SetAgentConfigOverride Failed to fetch agent", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+		return nil, err
+	}
+
+	var resp *response.AgentConfigOverrideResponse
+	if s.approvalRequired(agent.Namespace) {
+		resp, err = s.proposeAgentConfigOverride(ctx, queryTx, agent, payload)
+	} else {
+		resp, err = s.setAgentConfigOverride(ctx, queryTx, agent, payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("SetAgentConfigOverride Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// checkAgentConfigOverride validates the override applied to the latest
+// config of the agent's namespace.
+func checkAgentConfigOverride(ctx context.Context, queryTx *queries.Queries, agent queries.Agent, payload request.AgentConfigOverrideRequest) error {
+	if len(payload.Config) == 0 {
+		return nil
+	}
+
+	latestGlobalConfig, err := queryTx.GetLatestVersionGlobalConfig(ctx, agent.Namespace)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		slog.Error("checkAgentConfigOverride Failed to fetch global config", slog.Any("error", err), slog.String("namespace", agent.Namespace))
+		return err
+	}
+
+	merged, err := mergePatch(latestGlobalConfig.Config, payload.Config)
+	if err != nil {
+		slog.Error("checkAgentConfigOverride Failed to apply agent config override", slog.Any("error", err))
+		return err
+	}
+	return validateConfig(ctx, queryTx, agent.Namespace, merged)
+}
+
+// setAgentConfigOverride writes an override change through queryTx, see
+// SetAgentConfigOverride.
+func (s *ControllerService) setAgentConfigOverride(ctx context.Context, queryTx *queries.Queries, agent queries.Agent, payload request.AgentConfigOverrideRequest) (*response.AgentConfigOverrideResponse, error) {
+	agentID := agent.ID
+
+	if err := checkAgentConfigOverride(ctx, queryTx, agent, payload); err != nil {
+		return nil, err
+	}
+
+	config := payload.Config
+	if len(config) == 0 {
+		config = json.RawMessage("{}")
+	}
+
+	var previous *response.AgentConfigOverrideResponse
+	previousOverride, err := queryTx.GetAgentConfigOverride(ctx, agentID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		slog.Error("setAgentConfigOverride Failed to fetch agent config override", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+		return nil, err
+	default:
+		previous = toAgentConfigOverrideResponse(previousOverride)
+	}
+
+	override, err := queryTx.UpsertAgentConfigOverride(ctx, queries.UpsertAgentConfigOverrideParams{
+		AgentID: agentID,
+		Config:  config,
+	})
+	if err != nil {
+		slog.Error("setAgentConfigOverride Failed to upsert agent config override", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+		return nil, err
+	}
+
+	if err := queryTx.BumpAgentConfigRevision(ctx, agentID); err != nil {
+		slog.Error("setAgentConfigOverride Failed to bump agent config revision", slog.Any("error", err), slog.String("agent_id", agentID.String()))
+		return nil, err
+	}
+
+	resp := toAgentConfigOverrideResponse(override)
+	auditAction := response.AuditActionAgentOverrideSet
+	if len(payload.Config) == 0 {
+		auditAction = response.AuditActionAgentOverrideDelete
+	}
+	if err := recordAudit(ctx, queryTx, auditAction, agentResource(agentID)+"/config-override", previous, resp); err != nil {
+		slog.Error("setAgentConfigOverride Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	// wakes the agent's watch so the new effective config is served at once
+	if err := queryTx.NotifyGlobalConfigUpdated(ctx, agent.Namespace); err != nil {
+		slog.Error("setAgentConfigOverride Failed to notify global config update", slog.Any("error", err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func toAgentConfigOverrideResponse(override queries.AgentConfigOverride) *response.AgentConfigOverrideResponse {
+	return &response.AgentConfigOverrideResponse{
+		AgentID:   override.AgentID.String(),
+		Config:    override.Config,
+		Version:   override.Version,
+		UpdatedAt: override.UpdatedAt,
+	}
+}
+
+// mergePatch applies a JSON merge patch (RFC 7386) to a JSON document.
+func mergePatch(document, patch json.RawMessage) (json.RawMessage, error) {
+	target, err := decodeDocument(document)
+	if err != nil {
+		return nil, err
+	}
+	patchValue, err := decodeDocument(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(applyMergePatch(target, patchValue))
+}
+
+func applyMergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = applyMergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/requestctx"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// configSyncExtensions are the files RunConfigSync reads; YAML is converted
+// to JSON, which it is a superset of.
+var configSyncExtensions = []string{".json", ".yaml", ".yml"}
+
+// RunConfigSync imports the config documents of dir, checking every
+// interval until ctx is done. Each <namespace>.json, .yaml or .yml file
+// holds the document of that namespace; a file is imported when its content
+// changes, and once at start. Removing a file leaves the namespace as it is.
+func (s *ControllerService) RunConfigSync(ctx context.Context, dir string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// content hash of every file last imported, by file name
+	imported := make(map[string]string)
+
+	for {
+		s.syncConfigDir(ctx, dir, imported)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ControllerService) syncConfigDir(ctx context.Context, dir string, imported map[string]string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		slog.Error("RunConfigSync Failed to read config directory", slog.Any("error", err), slog.String("dir", dir))
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		namespace, ok := configSyncNamespace(name)
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			slog.Error("RunConfigSync Failed to read config file", slog.Any("error", err), slog.String("file", name))
+			continue
+		}
+
+		sum := sha256.Sum256(content)
+		hash := hex.EncodeToString(sum[:])
+		if imported[name] == hash {
+			continue
+		}
+
+		// a rejected file waits for its next change, other failures are
+		// retried on the next check
+		err = s.importConfigFile(ctx, name, namespace, content)
+		if err != nil {
+			slog.Error("RunConfigSync Failed to import config file", slog.Any("error", err), slog.String("file", name))
+		}
+		if err == nil || errors.Is(err, ErrInvalidConfig) || errors.Is(err, ErrInvalidSchema) {
+			imported[name] = hash
+		}
+	}
+}
+
+// importConfigFile imports the document of a synced file, acting as
+// config-sync:<file> in the audit log and for approvals.
+func (s *ControllerService) importConfigFile(ctx context.Context, name, namespace string, content []byte) error {
+	document, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, err)
+	}
+
+	payload := request.ConfigImportRequest{
+		Namespaces: []request.ConfigImportNamespace{{Namespace: namespace, Config: document}},
+	}
+	if err := payload.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, err)
+	}
+
+	ctx = requestctx.WithInfo(ctx, requestctx.Info{Actor: "config-sync:" + name})
+	resp, err := s.ImportConfig(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range resp.Items {
+		slog.Info("RunConfigSync imported config file", slog.String("file", name), slog.String("namespace", result.Namespace), slog.String("config", result.Config), slog.Int64("version", result.Version))
+	}
+	return nil
+}
+
+// configSyncNamespace returns the namespace a file name holds the document
+// of, and false for files RunConfigSync ignores.
+func configSyncNamespace(name string) (string, bool) {
+	ext := filepath.Ext(name)
+	if !slices.Contains(configSyncExtensions, ext) {
+		return "", false
+	}
+	return strings.TrimSuffix(name, ext), true
+}
@@ -6,130 +6,549 @@ import (
 	"controller-service/internal/api/response"
 	"controller-service/internal/repository"
 	queries "controller-service/internal/repository/sqlc"
+	"controller-service/internal/signing"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type ControllerService struct {
-	DB   *sql.DB
-	Repo repository.IRepository
+	DB       *sql.DB
+	Repo     repository.IRepository
+	Notifier IConfigNotifier
+	// AgentStaleAfter is how long an agent may go without a heartbeat
+	// before it is reported as stale.
+	AgentStaleAfter time.Duration
+	// Signer signs served configs; nil serves them unsigned.
+	Signer *signing.Signer
+	// ApprovalNamespaces lists the namespaces whose updates, rollbacks and
+	// agent overrides are stored as proposals until a second key holder
+	// approves them, for ProposalTTL.
+	ApprovalNamespaces []string
+	ProposalTTL        time.Duration
+	// Draining is closed when the server shuts down, so open watches return
+	// instead of holding the shutdown up.
+	Draining <-chan struct{}
+}
+
+// IConfigNotifier signals committed config changes to long-poll watchers.
+type IConfigNotifier interface {
+	Changed() <-chan struct{}
 }
 
+// globalConfig holds the config document fields agents act on themselves;
+// the whole document is passed on to the worker.
 type globalConfig struct {
 	URL          string `json:"url"`
 	PollInterval int    `json:"poll_interval"`
 }
 
-func NewControllerService(db *sql.DB, repo repository.IRepository) IControllerService {
+func NewControllerService(db *sql.DB, repo repository.IRepository, notifier IConfigNotifier) IControllerService {
 	return &ControllerService{
-		DB:   db,
-		Repo: repo,
+		DB:       db,
+		Repo:     repo,
+		Notifier: notifier,
 	}
 }
 
 //go:generate mockgen -destination=mocks/controller_usecase.go -source=controller.go IControllerService
 type IControllerService interface {
-	RegisterAgent(ctx context.Context, name string) (*response.ConfigResponse, error)
-	GetConfig(ctx context.Context) (*response.ConfigResponse, int, error)
-	UpdateConfig(ctx context.Context, payload request.UpdateConfigRequest) error
+	RegisterAgent(ctx context.Context, payload request.RegisterAgentRequest) (*response.ConfigResponse, error)
+	GetConfig(ctx context.Context, namespace, agentID string) (*response.ConfigResponse, int, error)
+	UpdateConfig(ctx context.Context, namespace string, payload request.UpdateConfigRequest) (*response.ConfigUpdateResponse, error)
+	WatchConfig(ctx context.Context, namespace, agentID string, sinceVersion int) (*response.ConfigResponse, int, error)
+
+	// Config history
+	ListConfigVersions(ctx context.Context, namespace string, pagination request.PaginationRequest) (*response.ConfigVersionListResponse, error)
+	GetConfigVersion(ctx context.Context, namespace string, version int64) (*response.ConfigVersionResponse, error)
+	DiffConfigVersions(ctx context.Context, namespace string, from, to int64) (*response.ConfigDiffResponse, error)
+	RollbackConfig(ctx context.Context, namespace string, version int64) (*response.ConfigVersionResponse, error)
+	GetConfigRollout(ctx context.Context, namespace string, version int64) (*response.ConfigRolloutResponse, error)
+	PromoteConfigVersion(ctx context.Context, namespace string, version int64, percentage int) (*response.ConfigVersionResponse, error)
+	AbortConfigVersion(ctx context.Context, namespace string, version int64) (*response.ConfigVersionResponse, error)
+
+	// Scheduled config
+	ListScheduledConfigs(ctx context.Context, namespace string) (*response.ScheduledConfigListResponse, error)
+	CancelScheduledConfig(ctx context.Context, namespace string, id uuid.UUID) (*response.ScheduledConfigResponse, error)
+
+	// Config proposals
+	ListConfigProposals(ctx context.Context, namespace string, filter request.ConfigProposalFilter) (*response.ConfigProposalListResponse, error)
+	ApproveConfigProposal(ctx context.Context, namespace string, id uuid.UUID) (*response.ConfigUpdateResponse, error)
+
+	// Config bundles
+	ExportConfig(ctx context.Context) (*response.ConfigBundle, error)
+	ImportConfig(ctx context.Context, payload request.ConfigImportRequest) (*response.ConfigImportResponse, error)
+
+	// Agent fleet
+	ReregisterAgent(ctx context.Context, agentID uuid.UUID, payload request.RegisterAgentRequest) (*response.ConfigResponse, error)
+	RecordHeartbeat(ctx context.Context, agentID uuid.UUID, payload request.AgentHeartbeatRequest) error
+	DeregisterAgent(ctx context.Context, agentID uuid.UUID) error
+	ListAgents(ctx context.Context, namespace string) (*response.AgentListResponse, error)
+
+	// Config schema
+	GetConfigSchema(ctx context.Context, namespace string) (*response.ConfigSchemaResponse, error)
+	SetConfigSchema(ctx context.Context, namespace string, schema json.RawMessage) (*response.ConfigSchemaResponse, error)
+	GetAgentConfigOverride(ctx context.Context, agentID uuid.UUID) (*response.AgentConfigOverrideResponse, error)
+	SetAgentConfigOverride(ctx context.Context, agentID uuid.UUID, payload request.AgentConfigOverrideRequest) (*response.AgentConfigOverrideResponse, error)
+
+	// Audit
+	ListAuditEvents(ctx context.Context, filter request.AuditFilter, pagination request.PaginationRequest) (*response.AuditEventListResponse, error)
+	ExportAuditEvents(ctx context.Context, filter request.AuditFilter, emit func(response.AuditEventResponse) error) error
+
+	// API keys
+	CreateAPIKey(ctx context.Context, payload request.CreateAPIKeyRequest) (*response.CreatedAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context) (*response.APIKeyListResponse, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) (*response.APIKeyResponse, error)
+	AuthenticateAPIKey(ctx context.Context, key string) (*response.APIKeyResponse, error)
+
+	// Webhook
+	CreateWebhook(ctx context.Context, payload request.WebhookRequest) (*response.CreatedWebhookResponse, error)
+	ListWebhooks(ctx context.Context) (*response.WebhookListResponse, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (*response.WebhookResponse, error)
+	UpdateWebhook(ctx context.Context, id uuid.UUID, payload request.WebhookRequest) (*response.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) (*response.WebhookResponse, error)
+	ListWebhookDeliveries(ctx context.Context, id uuid.UUID, filter request.WebhookDeliveryFilter, pagination request.PaginationRequest) (*response.WebhookDeliveryListResponse, error)
 }
 
-func (s *ControllerService) RegisterAgent(ctx context.Context, name string) (*response.ConfigResponse, error) {
-	latestGlobalConfig, err := s.Repo.GetLatestVersionGlobalConfig(ctx)
+func (s *ControllerService) RegisterAgent(ctx context.Context, payload request.RegisterAgentRequest) (*response.ConfigResponse, error) {
+	namespace := payload.Namespace
+	if namespace == "" {
+		namespace = request.DefaultNamespace
+	}
+
+	config, err := s.registrationConfig(ctx, namespace)
 	if err != nil {
-		slog.Error("RegisterAgent Failed to fetch global config", slog.Any("error", err))
+		slog.Error("RegisterAgent Failed to fetch global config", slog.Any("error", err), slog.String("namespace", namespace))
 		return nil, err
 	}
 
-	var globalConfig globalConfig
-	err = json.Unmarshal(latestGlobalConfig.Config, &globalConfig)
+	labels, err := marshalLabels(payload.Labels)
 	if err != nil {
-		slog.Error("RegisterAgent Failed to unmarshal global config", slog.Any("error", err))
+		slog.Error("RegisterAgent Failed to marshal labels", slog.Any("error", err))
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("RegisterAgent Failed to begin transaction", slog.Any("error", err))
 		return nil, err
 	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
 
-	agentID, err := s.Repo.CreateAgent(ctx, name)
+	identity := callerIdentity(ctx)
+	agentID, err := queryTx.CreateAgent(ctx, queries.CreateAgentParams{
+		Name:         payload.Name,
+		Namespace:    namespace,
+		Labels:       labels,
+		RegisteredBy: identity,
+	})
 	if err != nil {
 		slog.Error("RegisterAgent Failed to create agent", slog.Any("error", err))
 		return nil, err
 	}
 
+	registered := agentAuditState{
+		ID:           agentID.String(),
+		Name:         payload.Name,
+		Namespace:    namespace,
+		Labels:       payload.Labels,
+		RegisteredBy: identity,
+	}
+	if err := recordAudit(ctx, queryTx, response.AuditActionAgentRegister, agentResource(agentID), nil, registered); err != nil {
+		slog.Error("RegisterAgent Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("RegisterAgent Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	resp, err := toConfigResponse(namespace, config)
+	if err != nil {
+		slog.Error("RegisterAgent Failed to unmarshal global config", slog.Any("error", err))
+		return nil, err
+	}
+	resp.AgentID = agentID.String()
+
+	return resp, nil
+}
+
+// registrationConfig returns the latest config document of the namespace, or
+// an empty one when nothing has been published to it yet.
+func (s *ControllerService) registrationConfig(ctx context.Context, namespace string) (json.RawMessage, error) {
+	latestGlobalConfig, err := s.Repo.GetLatestVersionGlobalConfig(ctx, namespace)
+	if errors.Is(err, sql.ErrNoRows) {
+		return json.RawMessage("{}"), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return latestGlobalConfig.Config, nil
+}
+
+func toConfigResponse(namespace string, config json.RawMessage) (*response.ConfigResponse, error) {
+	var globalConfig globalConfig
+	if err := json.Unmarshal(config, &globalConfig); err != nil {
+		return nil, err
+	}
+
 	return &response.ConfigResponse{
-		AgentID:      agentID.String(),
+		Namespace:    namespace,
 		PollURL:      globalConfig.URL,
 		PollInterval: globalConfig.PollInterval,
+		Config:       config,
 	}, nil
 }
 
-func (s *ControllerService) GetConfig(ctx context.Context) (*response.ConfigResponse, int, error) {
-	latestGlobalConfig, err := s.Repo.GetLatestVersionGlobalConfig(ctx)
+// resolveCaller picks the namespace of a config request: the explicit one
+// when given, otherwise the namespace the calling agent registered with,
+// otherwise the default namespace. The calling agent is returned when it is
+// known to the controller; an agent registered by another identity is
+// rejected with ErrAgentForbidden, so a key cannot read the override of an
+// agent it does not own by sending its ID.
+func (s *ControllerService) resolveCaller(ctx context.Context, namespace, agentID string) (string, *queries.Agent, error) {
+	var caller *queries.Agent
+
+	if agentID != "" {
+		id, err := uuid.Parse(agentID)
+		if err != nil {
+			return "", nil, ErrInvalidAgentID
+		}
+
+		agent, err := s.Repo.GetAgent(ctx, id)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			slog.Error("resolveCaller Failed to fetch agent", slog.Any("error", err), slog.String("agent_id", agentID))
+			return "", nil, err
+		case !ownsAgent(agent, callerIdentity(ctx)):
+			return "", nil, ErrAgentForbidden
+		default:
+			caller = &agent
+		}
+	}
+
+	switch {
+	case namespace != "":
+	case caller != nil:
+		namespace = caller.Namespace
+	default:
+		namespace = request.DefaultNamespace
+	}
+
+	return namespace, caller, nil
+}
+
+// GetConfig returns the config version served to the caller, the latest
+// active one or a canary the caller is selected for, with the override of
+// the calling agent applied. The returned version is the config revision of
+// the caller, see configRevision. With a Signer the response carries the
+// signature of that version and config, bound to the namespace and the
+// calling agent.
+func (s *ControllerService) GetConfig(ctx context.Context, namespace, agentID string) (*response.ConfigResponse, int, error) {
+	namespace, caller, err := s.resolveCaller(ctx, namespace, agentID)
 	if err != nil {
-		slog.Error("GetConfig Failed to fetch global config", slog.Any("error", err))
 		return nil, 0, err
 	}
 
-	var globalConfig globalConfig
-	err = json.Unmarshal(latestGlobalConfig.Config, &globalConfig)
+	latestGlobalConfig, err := s.servedGlobalConfig(ctx, namespace, caller)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("GetConfig Failed to fetch global config", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, 0, err
+	}
+
+	config := latestGlobalConfig.Config
+	version := latestGlobalConfig.Version
+
+	// overrides only apply to the namespace the agent is registered in
+	var overrideVersion int64
+	if caller != nil && caller.Namespace == namespace {
+		override, err := s.Repo.GetAgentConfigOverride(ctx, caller.ID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			slog.Error("GetConfig Failed to fetch agent config override", slog.Any("error", err), slog.String("agent_id", agentID))
+			return nil, 0, err
+		default:
+			config, err = mergePatch(config, override.Config)
+			if err != nil {
+				slog.Error("GetConfig Failed to apply agent config override", slog.Any("error", err), slog.String("agent_id", agentID))
+				return nil, 0, err
+			}
+			overrideVersion = override.Version
+		}
+	}
+
+	revision, err := s.configRevision(ctx, namespace, caller)
+	if err != nil {
+		slog.Error("GetConfig Failed to fetch config revision", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, 0, err
+	}
+
+	resp, err := toConfigResponse(namespace, config)
 	if err != nil {
 		slog.Error("GetConfig Failed to unmarshal global config", slog.Any("error", err))
 		return nil, 0, err
 	}
+	resp.GlobalVersion = version
+	resp.OverrideVersion = overrideVersion
 
-	return &response.ConfigResponse{
-		PollURL:      globalConfig.URL,
-		PollInterval: globalConfig.PollInterval,
-	}, int(latestGlobalConfig.Version), nil
+	if s.Signer != nil {
+		var callerID string
+		if caller != nil {
+			callerID = caller.ID.String()
+		}
+		resp.Signature, err = s.Signer.Sign(revision, namespace, callerID, config)
+		if err != nil {
+			slog.Error("GetConfig Failed to sign config", slog.Any("error", err))
+			return nil, 0, err
+		}
+	}
+
+	return resp, int(revision), nil
+}
+
+// configRevision returns the version of the config served to the caller.
+// Revisions are drawn from a single sequence: a namespace takes a new one
+// whenever a version is published or a rollout changes, an agent whenever
+// its override, labels or namespace change. The highest of the two is
+// therefore new, and higher than any before, whenever the served config may
+// have changed, e.g. after a canary abort.
+func (s *ControllerService) configRevision(ctx context.Context, namespace string, caller *queries.Agent) (int64, error) {
+	revision, err := s.Repo.GetRevisionGlobalConfig(ctx, namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	if caller != nil {
+		revision = max(revision, caller.ConfigRevision)
+	}
+	return revision, nil
 }
 
-func (s *ControllerService) UpdateConfig(ctx context.Context, payload request.UpdateConfigRequest) error {
-	tx, err := s.DB.Begin()
+// UpdateConfig publishes a new config version. When payload.ExpectedVersion
+// is set and is not the current version of the namespace, the latest version
+// that was not aborted, nothing is written and a *VersionConflictError is
+// returned. In namespaces requiring approval, a proposal is stored instead.
+func (s *ControllerService) UpdateConfig(ctx context.Context, namespace string, payload request.UpdateConfigRequest) (*response.ConfigUpdateResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
 		slog.Error("UpdateConfig Failed to begin transaction", slog.Any("error", err))
-		return err
+		return nil, err
 	}
 	defer tx.Rollback()
 
 	queryTx := s.Repo.WithTx(tx)
 
-	latestGlobalConfig, err := queryTx.GetLatestVersionGlobalConfig(ctx)
+	resp, err := s.submitConfig(ctx, queryTx, namespace, payload)
 	if err != nil {
-		slog.Error("UpdateConfig Failed to fetch global config", slog.Any("error", err))
-		return err
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("UpdateConfig Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// submitConfig writes an update through queryTx, as a proposal when the
+// namespace requires approval.
+func (s *ControllerService) submitConfig(ctx context.Context, queryTx *queries.Queries, namespace string, payload request.UpdateConfigRequest) (*response.ConfigUpdateResponse, error) {
+	if s.approvalRequired(namespace) {
+		return s.proposeConfig(ctx, queryTx, namespace, payload)
 	}
+	return s.updateConfig(ctx, queryTx, namespace, payload)
+}
 
-	var latestConfig globalConfig
-	err = json.Unmarshal(latestGlobalConfig.Config, &latestConfig)
+// checkConfigUpdate validates payload against the namespace schema and its
+// expected version, returning the current version of the namespace.
+func checkConfigUpdate(ctx context.Context, queryTx *queries.Queries, namespace string, payload request.UpdateConfigRequest) (*queries.GlobalConfig, error) {
+	if err := validateConfig(ctx, queryTx, namespace, payload.Config); err != nil {
+		return nil, err
+	}
+
+	previousGlobalConfig, err := currentGlobalConfig(ctx, queryTx, namespace)
 	if err != nil {
-		slog.Error("UpdateConfig Failed to unmarshal global config", slog.Any("error", err))
-		return err
+		slog.Error("checkConfigUpdate Failed to fetch current global config", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	if payload.ExpectedVersion != nil {
+		var currentVersion int64
+		if previousGlobalConfig != nil {
+			currentVersion = previousGlobalConfig.Version
+		}
+		if currentVersion != *payload.ExpectedVersion {
+			return nil, &VersionConflictError{CurrentVersion: currentVersion}
+		}
 	}
 
-	if latestConfig.URL == payload.URL && latestConfig.PollInterval == payload.PollInterval {
-		slog.Info("UpdateConfig config is already up to date", slog.Any("url", payload.URL), slog.Any("poll_interval", payload.PollInterval))
-		return nil
+	return previousGlobalConfig, nil
+}
+
+// updateConfig writes the version of an update through queryTx, leaving the
+// commit to the caller.
+func (s *ControllerService) updateConfig(ctx context.Context, queryTx *queries.Queries, namespace string, payload request.UpdateConfigRequest) (*response.ConfigUpdateResponse, error) {
+	previousGlobalConfig, err := checkConfigUpdate(ctx, queryTx, namespace, payload)
+	if err != nil {
+		return nil, err
 	}
 
-	globalConfig := globalConfig{
-		URL:          payload.URL,
-		PollInterval: payload.PollInterval,
+	if payload.EffectiveAt != nil {
+		return s.scheduleConfig(ctx, queryTx, namespace, payload, previousGlobalConfig)
 	}
 
-	configBytes, err := json.Marshal(globalConfig)
+	// a new version supersedes any canary still rolling out
+	aborted, err := queryTx.AbortCanaryGlobalConfigs(ctx, namespace)
 	if err != nil {
-		slog.Error("UpdateConfig Failed to marshal global config", slog.Any("error", err))
-		return err
+		slog.Error("updateConfig Failed to abort canary global configs", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
 	}
 
-	if _, err = queryTx.CreateGlobalConfig(ctx, queries.CreateGlobalConfigParams{
-		Config:  configBytes,
-		Version: latestGlobalConfig.Version + 1,
-	}); err != nil {
-		slog.Error("UpdateConfig Failed to create global config", slog.Any("error", err))
+	latestGlobalConfig, err := queryTx.GetLatestVersionGlobalConfig(ctx, namespace)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		slog.Error("updateConfig Failed to fetch global config", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	case payload.Rollout == nil:
+		unchanged, err := sameDocument(latestGlobalConfig.Config, payload.Config)
+		if err != nil {
+			slog.Error("updateConfig Failed to compare global config", slog.Any("error", err))
+			return nil, err
+		}
+
+		if unchanged {
+			slog.Info("updateConfig config is already up to date", slog.String("namespace", namespace), slog.Int64("version", latestGlobalConfig.Version))
+			if aborted == 0 {
+				return toConfigUpdateResponse(latestGlobalConfig), nil
+			}
+
+			// canary agents go back to the active version
+			if err := recordAudit(ctx, queryTx, response.AuditActionConfigUpdate, configResource(namespace), configAuditState(previousGlobalConfig), configAuditState(&latestGlobalConfig)); err != nil {
+				slog.Error("updateConfig Failed to record audit event", slog.Any("error", err))
+				return nil, err
+			}
+			if err := enqueueAbortEvent(ctx, queryTx, &latestGlobalConfig, previousGlobalConfig); err != nil {
+				slog.Error("updateConfig Failed to enqueue webhook event", slog.Any("error", err))
+				return nil, err
+			}
+			if err := queryTx.NotifyGlobalConfigUpdated(ctx, namespace); err != nil {
+				slog.Error("updateConfig Failed to notify global config update", slog.Any("error", err))
+				return nil, err
+			}
+			return toConfigUpdateResponse(latestGlobalConfig), nil
+		}
+	}
+
+	// versions are counted per namespace, a new namespace starts at 1; aborted
+	// canaries keep their number
+	latestVersion, err := queryTx.GetMaxVersionGlobalConfig(ctx, namespace)
+	if err != nil {
+		slog.Error("updateConfig Failed to fetch latest version", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	rollout, err := newRolloutParams(payload.Rollout)
+	if err != nil {
+		slog.Error("updateConfig Failed to marshal rollout selector", slog.Any("error", err))
+		return nil, err
+	}
+
+	newGlobalConfig, err := queryTx.CreateGlobalConfig(ctx, queries.CreateGlobalConfigParams{
+		Namespace:         namespace,
+		Config:            payload.Config,
+		Version:           latestVersion + 1,
+		Status:            rollout.status,
+		RolloutPercentage: rollout.percentage,
+		RolloutSelector:   rollout.selector,
+	})
+	if isUniqueViolation(err) {
+		return nil, s.versionConflict(ctx, namespace)
+	}
+	if err != nil {
+		slog.Error("updateConfig Failed to create global config", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, queryTx, response.AuditActionConfigUpdate, configResource(namespace), configAuditState(previousGlobalConfig), configAuditState(&newGlobalConfig)); err != nil {
+		slog.Error("updateConfig Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := enqueueConfigEvent(ctx, queryTx, response.AuditActionConfigUpdate, previousGlobalConfig, newGlobalConfig); err != nil {
+		slog.Error("updateConfig Failed to enqueue webhook event", slog.Any("error", err))
+		return nil, err
+	}
+
+	// delivered to watchers only once the transaction commits
+	if err := queryTx.NotifyGlobalConfigUpdated(ctx, namespace); err != nil {
+		slog.Error("updateConfig Failed to notify global config update", slog.Any("error", err))
+		return nil, err
+	}
+
+	return toConfigUpdateResponse(newGlobalConfig), nil
+}
+
+// versionConflict builds the error for a write that lost its version number
+// to a concurrent writer. It reads outside the failed transaction, which no
+// longer accepts queries.
+func (s *ControllerService) versionConflict(ctx context.Context, namespace string) error {
+	currentVersion, err := s.Repo.GetCurrentVersionGlobalConfig(ctx, namespace)
+	if err != nil {
+		slog.Error("versionConflict Failed to fetch current version", slog.Any("error", err), slog.String("namespace", namespace))
 		return err
 	}
+	return &VersionConflictError{CurrentVersion: currentVersion}
+}
+
+func toConfigUpdateResponse(config queries.GlobalConfig) *response.ConfigUpdateResponse {
+	return &response.ConfigUpdateResponse{
+		Namespace: config.Namespace,
+		Version:   config.Version,
+		Status:    config.Status,
+		ETag:      response.ETag(config.Version),
+	}
+}
+
+// WatchConfig blocks until the latest config version differs from
+// sinceVersion or ctx is done or the server drains, in which case
+// ErrNotModified is returned.
+func (s *ControllerService) WatchConfig(ctx context.Context, namespace, agentID string, sinceVersion int) (*response.ConfigResponse, int, error) {
+	for {
+		changed := s.Notifier.Changed()
 
-	tx.Commit()
+		config, version, err := s.GetConfig(ctx, namespace, agentID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			if ctx.Err() != nil {
+				return nil, sinceVersion, ErrNotModified
+			}
+			return nil, 0, err
+		}
 
-	return nil
+		if err == nil && version != sinceVersion {
+			return config, version, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, version, ErrNotModified
+		case <-s.Draining:
+			return nil, version, ErrNotModified
+		case <-changed:
+		}
+	}
 }
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/response"
+	"controller-service/internal/repository"
+	queries "controller-service/internal/repository/sqlc"
+	"controller-service/internal/requestctx"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// revisionRepo holds the config rows of the namespaces and one agent, taking
+// revisions from a sequence where the queries call nextval. Any other query
+// panics through the nil embedded IRepository.
+type revisionRepo struct {
+	repository.IRepository
+
+	seq      int64
+	agent    queries.Agent
+	configs  []queries.GlobalConfig
+	override *queries.AgentConfigOverride
+}
+
+func (r *revisionRepo) nextRevision() int64 {
+	r.seq++
+	return r.seq
+}
+
+func (r *revisionRepo) publish(namespace string, version int64, status string, config string) {
+	r.configs = append(r.configs, queries.GlobalConfig{
+		Namespace:         namespace,
+		Config:            json.RawMessage(config),
+		Version:           version,
+		Status:            status,
+		RolloutPercentage: 100,
+		RolloutSelector:   json.RawMessage("{}"),
+		Revision:          r.nextRevision(),
+	})
+}
+
+func (r *revisionRepo) setStatus(version int64, status string) {
+	for i := range r.configs {
+		if r.configs[i].Version == version {
+			r.configs[i].Status = status
+			r.configs[i].Revision = r.nextRevision()
+		}
+	}
+}
+
+func (r *revisionRepo) setOverride(config string) {
+	version := int64(1)
+	if r.override != nil {
+		version = r.override.Version + 1
+	}
+	r.override = &queries.AgentConfigOverride{AgentID: r.agent.ID, Config: json.RawMessage(config), Version: version}
+	r.agent.ConfigRevision = r.nextRevision()
+}
+
+func (r *revisionRepo) moveAgent(namespace string) {
+	r.agent.Namespace = namespace
+	r.agent.ConfigRevision = r.nextRevision()
+}
+
+func (r *revisionRepo) GetAgent(ctx context.Context, id uuid.UUID) (queries.Agent, error) {
+	return r.agent, nil
+}
+
+func (r *revisionRepo) latest(namespace, status string) (queries.GlobalConfig, error) {
+	var latest *queries.GlobalConfig
+	for i, config := range r.configs {
+		if config.Namespace == namespace && config.Status == status && (latest == nil || config.Version > latest.Version) {
+			latest = &r.configs[i]
+		}
+	}
+	if latest == nil {
+		return queries.GlobalConfig{}, sql.ErrNoRows
+	}
+	return *latest, nil
+}
+
+func (r *revisionRepo) GetLatestVersionGlobalConfig(ctx context.Context, namespace string) (queries.GlobalConfig, error) {
+	return r.latest(namespace, response.ConfigStatusActive)
+}
+
+func (r *revisionRepo) GetLatestCanaryGlobalConfig(ctx context.Context, namespace string) (queries.GlobalConfig, error) {
+	return r.latest(namespace, response.ConfigStatusCanary)
+}
+
+func (r *revisionRepo) GetRevisionGlobalConfig(ctx context.Context, namespace string) (int64, error) {
+	var revision int64
+	for _, config := range r.configs {
+		if config.Namespace == namespace {
+			revision = max(revision, config.Revision)
+		}
+	}
+	return revision, nil
+}
+
+func (r *revisionRepo) GetAgentConfigOverride(ctx context.Context, agentID uuid.UUID) (queries.AgentConfigOverride, error) {
+	if r.override == nil {
+		return queries.AgentConfigOverride{}, sql.ErrNoRows
+	}
+	return *r.override, nil
+}
+
+func TestGetConfigRevisionIsMonotonic(t *testing.T) {
+	repo := &revisionRepo{
+		agent: queries.Agent{
+			ID:        uuid.New(),
+			Namespace: "default",
+			Labels:    json.RawMessage("{}"),
+		},
+	}
+	s := &ControllerService{Repo: repo}
+
+	steps := []struct {
+		name              string
+		change            func()
+		wantGlobalVersion int64
+		wantURL           string
+	}{
+		{
+			name:              "active version",
+			change:            func() { repo.publish("default", 5, response.ConfigStatusActive, `{"url":"https://v5"}`) },
+			wantGlobalVersion: 5,
+			wantURL:           "https://v5",
+		},
+		{
+			name:              "canary targeting every agent",
+			change:            func() { repo.publish("default", 6, response.ConfigStatusCanary, `{"url":"https://v6"}`) },
+			wantGlobalVersion: 6,
+			wantURL:           "https://v6",
+		},
+		{
+			name:              "canary aborted",
+			change:            func() { repo.setStatus(6, response.ConfigStatusAborted) },
+			wantGlobalVersion: 5,
+			wantURL:           "https://v5",
+		},
+		{
+			name:              "override on the active version",
+			change:            func() { repo.setOverride(`{"url":"https://override"}`) },
+			wantGlobalVersion: 5,
+			wantURL:           "https://override",
+		},
+		{
+			name:              "newer version under the override",
+			change:            func() { repo.publish("default", 7, response.ConfigStatusActive, `{"url":"https://v7"}`) },
+			wantGlobalVersion: 7,
+			wantURL:           "https://override",
+		},
+		{
+			name:              "override cleared",
+			change:            func() { repo.setOverride(`{}`) },
+			wantGlobalVersion: 7,
+			wantURL:           "https://v7",
+		},
+		{
+			name:              "scheduled config activated",
+			change:            func() { repo.publish("default", 8, response.ConfigStatusActive, `{"url":"https://v8"}`) },
+			wantGlobalVersion: 8,
+			wantURL:           "https://v8",
+		},
+		{
+			name: "agent moved to a namespace with lower versions",
+			change: func() {
+				repo.publish("staging", 2, response.ConfigStatusActive, `{"url":"https://staging"}`)
+				repo.moveAgent("staging")
+			},
+			wantGlobalVersion: 2,
+			wantURL:           "https://staging",
+		},
+	}
+
+	var previous int
+	served := make(map[int]string)
+	for _, step := range steps {
+		step.change()
+
+		config, version, err := s.GetConfig(context.Background(), "", repo.agent.ID.String())
+		if err != nil {
+			t.Fatalf("%s: GetConfig() error = %v", step.name, err)
+		}
+		if config.GlobalVersion != step.wantGlobalVersion || config.PollURL != step.wantURL {
+			t.Fatalf("%s: GetConfig() served version %d with url %q, want %d with %q", step.name, config.GlobalVersion, config.PollURL, step.wantGlobalVersion, step.wantURL)
+		}
+
+		if url, ok := served[version]; ok && url != config.PollURL {
+			t.Fatalf("%s: version %d served %q before, now %q", step.name, version, url, config.PollURL)
+		}
+		served[version] = config.PollURL
+
+		// a step that leaves the served config alone may keep its version
+		if version < previous {
+			t.Fatalf("%s: version went back from %d to %d", step.name, previous, version)
+		}
+		previous = version
+	}
+}
+
+func TestGetConfigOfAnotherIdentity(t *testing.T) {
+	repo := &revisionRepo{
+		agent: queries.Agent{
+			ID:           uuid.New(),
+			Namespace:    "default",
+			Labels:       json.RawMessage("{}"),
+			RegisteredBy: "api-key:agents",
+		},
+	}
+	repo.publish("default", 1, response.ConfigStatusActive, `{"url":"https://v1"}`)
+	repo.setOverride(`{"url":"https://override"}`)
+	s := &ControllerService{Repo: repo}
+
+	tests := []struct {
+		name    string
+		actor   string
+		wantURL string
+		wantErr error
+	}{
+		{
+			name:    "registering identity",
+			actor:   "api-key:agents",
+			wantURL: "https://override",
+		},
+		{
+			name:    "other identity",
+			actor:   "api-key:other",
+			wantErr: ErrAgentForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := requestctx.WithInfo(context.Background(), requestctx.Info{Actor: tt.actor})
+			config, _, err := s.GetConfig(ctx, "", repo.agent.ID.String())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetConfig() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && config.PollURL != tt.wantURL {
+				t.Errorf("GetConfig() url = %q, want %q", config.PollURL, tt.wantURL)
+			}
+		})
+	}
+}
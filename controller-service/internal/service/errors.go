@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrNotModified    = errors.New("not modified")
+	ErrInvalidAgentID = errors.New("invalid agent id")
+
+	// ErrAgentForbidden is returned for writes to an agent that was
+	// registered by another API key or client certificate.
+	ErrAgentForbidden = errors.New("agent is registered by another identity")
+
+	ErrRolloutNotInProgress = errors.New("rollout not in progress")
+
+	// ErrInvalidConfig and ErrInvalidSchema are wrapped with the reason the
+	// document was rejected.
+	ErrInvalidConfig = errors.New("invalid config")
+	ErrInvalidSchema = errors.New("invalid schema")
+
+	ErrVersionConflict = errors.New("config version conflict")
+
+	// ErrSelfApproval is returned when a proposal's author, or a key related
+	// to the author's, tries to approve it; approvals need a second key
+	// holder.
+	ErrSelfApproval       = errors.New("proposal must be approved by another key holder")
+	ErrProposalNotPending = errors.New("proposal is not pending")
+	ErrProposalExpired    = errors.New("proposal has expired")
+	// ErrProposalAgentGone is returned when the agent of an override
+	// proposal was deleted or moved to another namespace since.
+	ErrProposalAgentGone = errors.New("agent of the proposal is gone from its namespace")
+	// ErrProposalScheduleGone is returned when the scheduled config of a
+	// cancel proposal was activated or cancelled since.
+	ErrProposalScheduleGone = errors.New("scheduled config of the proposal is gone")
+
+	ErrAPIKeyExists = errors.New("api key name already exists")
+)
+
+// VersionConflictError is returned when a config write was based on a stale
+// version, or lost the race for its version number to another writer.
+// CurrentVersion is the latest version of the namespace.
+type VersionConflictError struct {
+	CurrentVersion int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s: current version is %d", ErrVersionConflict, e.CurrentVersion)
+}
+
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -8,11 +8,50 @@ import (
 	context "context"
 	request "controller-service/internal/api/request"
 	response "controller-service/internal/api/response"
+	json "encoding/json"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
+// MockIConfigNotifier is a mock of IConfigNotifier interface.
+type MockIConfigNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockIConfigNotifierMockRecorder
+}
+
+// MockIConfigNotifierMockRecorder is the mock recorder for MockIConfigNotifier.
+type MockIConfigNotifierMockRecorder struct {
+	mock *MockIConfigNotifier
+}
+
+// NewMockIConfigNotifier creates a new mock instance.
+func NewMockIConfigNotifier(ctrl *gomock.Controller) *MockIConfigNotifier {
+	mock := &MockIConfigNotifier{ctrl: ctrl}
+	mock.recorder = &MockIConfigNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIConfigNotifier) EXPECT() *MockIConfigNotifierMockRecorder {
+	return m.recorder
+}
+
+// Changed mocks base method.
+func (m *MockIConfigNotifier) Changed() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Changed")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// Changed indicates an expected call of Changed.
+func (mr *MockIConfigNotifierMockRecorder) Changed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Changed", reflect.TypeOf((*MockIConfigNotifier)(nil).Changed))
+}
+
 // MockIControllerService is a mock of IControllerService interface.
 type MockIControllerService struct {
 	ctrl     *gomock.Controller
@@ -36,10 +75,188 @@ func (m *MockIControllerService) EXPECT() *MockIControllerServiceMockRecorder {
 	return m.recorder
 }
 
+// AbortConfigVersion mocks base method.
+func (m *MockIControllerService) AbortConfigVersion(ctx context.Context, namespace string, version int64) (*response.ConfigVersionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortConfigVersion", ctx, namespace, version)
+	ret0, _ := ret[0].(*response.ConfigVersionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AbortConfigVersion indicates an expected call of AbortConfigVersion.
+func (mr *MockIControllerServiceMockRecorder) AbortConfigVersion(ctx, namespace, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortConfigVersion", reflect.TypeOf((*MockIControllerService)(nil).AbortConfigVersion), ctx, namespace, version)
+}
+
+// ApproveConfigProposal mocks base method.
+func (m *MockIControllerService) ApproveConfigProposal(ctx context.Context, namespace string, id uuid.UUID) (*response.ConfigUpdateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveConfigProposal", ctx, namespace, id)
+	ret0, _ := ret[0].(*response.ConfigUpdateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveConfigProposal indicates an expected call of ApproveConfigProposal.
+func (mr *MockIControllerServiceMockRecorder) ApproveConfigProposal(ctx, namespace, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveConfigProposal", reflect.TypeOf((*MockIControllerService)(nil).ApproveConfigProposal), ctx, namespace, id)
+}
+
+// AuthenticateAPIKey mocks base method.
+func (m *MockIControllerService) AuthenticateAPIKey(ctx context.Context, key string) (*response.APIKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", ctx, key)
+	ret0, _ := ret[0].(*response.APIKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockIControllerServiceMockRecorder) AuthenticateAPIKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockIControllerService)(nil).AuthenticateAPIKey), ctx, key)
+}
+
+// CancelScheduledConfig mocks base method.
+func (m *MockIControllerService) CancelScheduledConfig(ctx context.Context, namespace string, id uuid.UUID) (*response.ScheduledConfigResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledConfig", ctx, namespace, id)
+	ret0, _ := ret[0].(*response.ScheduledConfigResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduledConfig indicates an expected call of CancelScheduledConfig.
+func (mr *MockIControllerServiceMockRecorder) CancelScheduledConfig(ctx, namespace, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledConfig", reflect.TypeOf((*MockIControllerService)(nil).CancelScheduledConfig), ctx, namespace, id)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockIControllerService) CreateAPIKey(ctx context.Context, payload request.CreateAPIKeyRequest) (*response.CreatedAPIKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, payload)
+	ret0, _ := ret[0].(*response.CreatedAPIKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockIControllerServiceMockRecorder) CreateAPIKey(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockIControllerService)(nil).CreateAPIKey), ctx, payload)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockIControllerService) CreateWebhook(ctx context.Context, payload request.WebhookRequest) (*response.CreatedWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, payload)
+	ret0, _ := ret[0].(*response.CreatedWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockIControllerServiceMockRecorder) CreateWebhook(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockIControllerService)(nil).CreateWebhook), ctx, payload)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockIControllerService) DeleteWebhook(ctx context.Context, id uuid.UUID) (*response.WebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(*response.WebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockIControllerServiceMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockIControllerService)(nil).DeleteWebhook), ctx, id)
+}
+
+// DeregisterAgent mocks base method.
+func (m *MockIControllerService) DeregisterAgent(ctx context.Context, agentID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeregisterAgent", ctx, agentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeregisterAgent indicates an expected call of DeregisterAgent.
+func (mr *MockIControllerServiceMockRecorder) DeregisterAgent(ctx, agentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterAgent", reflect.TypeOf((*MockIControllerService)(nil).DeregisterAgent), ctx, agentID)
+}
+
+// DiffConfigVersions mocks base method.
+func (m *MockIControllerService) DiffConfigVersions(ctx context.Context, namespace string, from, to int64) (*response.ConfigDiffResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffConfigVersions", ctx, namespace, from, to)
+	ret0, _ := ret[0].(*response.ConfigDiffResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffConfigVersions indicates an expected call of DiffConfigVersions.
+func (mr *MockIControllerServiceMockRecorder) DiffConfigVersions(ctx, namespace, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffConfigVersions", reflect.TypeOf((*MockIControllerService)(nil).DiffConfigVersions), ctx, namespace, from, to)
+}
+
+// ExportAuditEvents mocks base method.
+func (m *MockIControllerService) ExportAuditEvents(ctx context.Context, filter request.AuditFilter, emit func(response.AuditEventResponse) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportAuditEvents", ctx, filter, emit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportAuditEvents indicates an expected call of ExportAuditEvents.
+func (mr *MockIControllerServiceMockRecorder) ExportAuditEvents(ctx, filter, emit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAuditEvents", reflect.TypeOf((*MockIControllerService)(nil).ExportAuditEvents), ctx, filter, emit)
+}
+
+// ExportConfig mocks base method.
+func (m *MockIControllerService) ExportConfig(ctx context.Context) (*response.ConfigBundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportConfig", ctx)
+	ret0, _ := ret[0].(*response.ConfigBundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportConfig indicates an expected call of ExportConfig.
+func (mr *MockIControllerServiceMockRecorder) ExportConfig(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportConfig", reflect.TypeOf((*MockIControllerService)(nil).ExportConfig), ctx)
+}
+
+// GetAgentConfigOverride mocks base method.
+func (m *MockIControllerService) GetAgentConfigOverride(ctx context.Context, agentID uuid.UUID) (*response.AgentConfigOverrideResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgentConfigOverride", ctx, agentID)
+	ret0, _ := ret[0].(*response.AgentConfigOverrideResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentConfigOverride indicates an expected call of GetAgentConfigOverride.
+func (mr *MockIControllerServiceMockRecorder) GetAgentConfigOverride(ctx, agentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentConfigOverride", reflect.TypeOf((*MockIControllerService)(nil).GetAgentConfigOverride), ctx, agentID)
+}
+
 // GetConfig mocks base method.
-func (m *MockIControllerService) GetConfig(ctx context.Context) (*response.ConfigResponse, int, error) {
+func (m *MockIControllerService) GetConfig(ctx context.Context, namespace, agentID string) (*response.ConfigResponse, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetConfig", ctx)
+	ret := m.ctrl.Call(m, "GetConfig", ctx, namespace, agentID)
 	ret0, _ := ret[0].(*response.ConfigResponse)
 	ret1, _ := ret[1].(int)
 	ret2, _ := ret[2].(error)
@@ -47,36 +264,367 @@ func (m *MockIControllerService) GetConfig(ctx context.Context) (*response.Confi
 }
 
 // GetConfig indicates an expected call of GetConfig.
-func (mr *MockIControllerServiceMockRecorder) GetConfig(ctx interface{}) *gomock.Call {
+func (mr *MockIControllerServiceMockRecorder) GetConfig(ctx, namespace, agentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockIControllerService)(nil).GetConfig), ctx, namespace, agentID)
+}
+
+// GetConfigRollout mocks base method.
+func (m *MockIControllerService) GetConfigRollout(ctx context.Context, namespace string, version int64) (*response.ConfigRolloutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigRollout", ctx, namespace, version)
+	ret0, _ := ret[0].(*response.ConfigRolloutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigRollout indicates an expected call of GetConfigRollout.
+func (mr *MockIControllerServiceMockRecorder) GetConfigRollout(ctx, namespace, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigRollout", reflect.TypeOf((*MockIControllerService)(nil).GetConfigRollout), ctx, namespace, version)
+}
+
+// GetConfigSchema mocks base method.
+func (m *MockIControllerService) GetConfigSchema(ctx context.Context, namespace string) (*response.ConfigSchemaResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigSchema", ctx, namespace)
+	ret0, _ := ret[0].(*response.ConfigSchemaResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigSchema indicates an expected call of GetConfigSchema.
+func (mr *MockIControllerServiceMockRecorder) GetConfigSchema(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigSchema", reflect.TypeOf((*MockIControllerService)(nil).GetConfigSchema), ctx, namespace)
+}
+
+// GetConfigVersion mocks base method.
+func (m *MockIControllerService) GetConfigVersion(ctx context.Context, namespace string, version int64) (*response.ConfigVersionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigVersion", ctx, namespace, version)
+	ret0, _ := ret[0].(*response.ConfigVersionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigVersion indicates an expected call of GetConfigVersion.
+func (mr *MockIControllerServiceMockRecorder) GetConfigVersion(ctx, namespace, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigVersion", reflect.TypeOf((*MockIControllerService)(nil).GetConfigVersion), ctx, namespace, version)
+}
+
+// GetWebhook mocks base method.
+func (m *MockIControllerService) GetWebhook(ctx context.Context, id uuid.UUID) (*response.WebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(*response.WebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockIControllerServiceMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockIControllerService)(nil).GetWebhook), ctx, id)
+}
+
+// ImportConfig mocks base method.
+func (m *MockIControllerService) ImportConfig(ctx context.Context, payload request.ConfigImportRequest) (*response.ConfigImportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportConfig", ctx, payload)
+	ret0, _ := ret[0].(*response.ConfigImportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportConfig indicates an expected call of ImportConfig.
+func (mr *MockIControllerServiceMockRecorder) ImportConfig(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportConfig", reflect.TypeOf((*MockIControllerService)(nil).ImportConfig), ctx, payload)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockIControllerService) ListAPIKeys(ctx context.Context) (*response.APIKeyListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx)
+	ret0, _ := ret[0].(*response.APIKeyListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockIControllerServiceMockRecorder) ListAPIKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockIControllerService)(nil).ListAPIKeys), ctx)
+}
+
+// ListAgents mocks base method.
+func (m *MockIControllerService) ListAgents(ctx context.Context, namespace string) (*response.AgentListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAgents", ctx, namespace)
+	ret0, _ := ret[0].(*response.AgentListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAgents indicates an expected call of ListAgents.
+func (mr *MockIControllerServiceMockRecorder) ListAgents(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAgents", reflect.TypeOf((*MockIControllerService)(nil).ListAgents), ctx, namespace)
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockIControllerService) ListAuditEvents(ctx context.Context, filter request.AuditFilter, pagination request.PaginationRequest) (*response.AuditEventListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", ctx, filter, pagination)
+	ret0, _ := ret[0].(*response.AuditEventListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockIControllerServiceMockRecorder) ListAuditEvents(ctx, filter, pagination interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockIControllerService)(nil).ListAuditEvents), ctx, filter, pagination)
+}
+
+// ListConfigProposals mocks base method.
+func (m *MockIControllerService) ListConfigProposals(ctx context.Context, namespace string, filter request.ConfigProposalFilter) (*response.ConfigProposalListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigProposals", ctx, namespace, filter)
+	ret0, _ := ret[0].(*response.ConfigProposalListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfigProposals indicates an expected call of ListConfigProposals.
+func (mr *MockIControllerServiceMockRecorder) ListConfigProposals(ctx, namespace, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockIControllerService)(nil).GetConfig), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigProposals", reflect.TypeOf((*MockIControllerService)(nil).ListConfigProposals), ctx, namespace, filter)
+}
+
+// ListConfigVersions mocks base method.
+func (m *MockIControllerService) ListConfigVersions(ctx context.Context, namespace string, pagination request.PaginationRequest) (*response.ConfigVersionListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigVersions", ctx, namespace, pagination)
+	ret0, _ := ret[0].(*response.ConfigVersionListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfigVersions indicates an expected call of ListConfigVersions.
+func (mr *MockIControllerServiceMockRecorder) ListConfigVersions(ctx, namespace, pagination interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigVersions", reflect.TypeOf((*MockIControllerService)(nil).ListConfigVersions), ctx, namespace, pagination)
+}
+
+// ListScheduledConfigs mocks base method.
+func (m *MockIControllerService) ListScheduledConfigs(ctx context.Context, namespace string) (*response.ScheduledConfigListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledConfigs", ctx, namespace)
+	ret0, _ := ret[0].(*response.ScheduledConfigListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledConfigs indicates an expected call of ListScheduledConfigs.
+func (mr *MockIControllerServiceMockRecorder) ListScheduledConfigs(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledConfigs", reflect.TypeOf((*MockIControllerService)(nil).ListScheduledConfigs), ctx, namespace)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockIControllerService) ListWebhookDeliveries(ctx context.Context, id uuid.UUID, filter request.WebhookDeliveryFilter, pagination request.PaginationRequest) (*response.WebhookDeliveryListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, id, filter, pagination)
+	ret0, _ := ret[0].(*response.WebhookDeliveryListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockIControllerServiceMockRecorder) ListWebhookDeliveries(ctx, id, filter, pagination interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockIControllerService)(nil).ListWebhookDeliveries), ctx, id, filter, pagination)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockIControllerService) ListWebhooks(ctx context.Context) (*response.WebhookListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].(*response.WebhookListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockIControllerServiceMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockIControllerService)(nil).ListWebhooks), ctx)
+}
+
+// PromoteConfigVersion mocks base method.
+func (m *MockIControllerService) PromoteConfigVersion(ctx context.Context, namespace string, version int64, percentage int) (*response.ConfigVersionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromoteConfigVersion", ctx, namespace, version, percentage)
+	ret0, _ := ret[0].(*response.ConfigVersionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PromoteConfigVersion indicates an expected call of PromoteConfigVersion.
+func (mr *MockIControllerServiceMockRecorder) PromoteConfigVersion(ctx, namespace, version, percentage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteConfigVersion", reflect.TypeOf((*MockIControllerService)(nil).PromoteConfigVersion), ctx, namespace, version, percentage)
+}
+
+// RecordHeartbeat mocks base method.
+func (m *MockIControllerService) RecordHeartbeat(ctx context.Context, agentID uuid.UUID, payload request.AgentHeartbeatRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordHeartbeat", ctx, agentID, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordHeartbeat indicates an expected call of RecordHeartbeat.
+func (mr *MockIControllerServiceMockRecorder) RecordHeartbeat(ctx, agentID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordHeartbeat", reflect.TypeOf((*MockIControllerService)(nil).RecordHeartbeat), ctx, agentID, payload)
 }
 
 // RegisterAgent mocks base method.
-func (m *MockIControllerService) RegisterAgent(ctx context.Context, name string) (*response.ConfigResponse, error) {
+func (m *MockIControllerService) RegisterAgent(ctx context.Context, payload request.RegisterAgentRequest) (*response.ConfigResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterAgent", ctx, name)
+	ret := m.ctrl.Call(m, "RegisterAgent", ctx, payload)
 	ret0, _ := ret[0].(*response.ConfigResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RegisterAgent indicates an expected call of RegisterAgent.
-func (mr *MockIControllerServiceMockRecorder) RegisterAgent(ctx, name interface{}) *gomock.Call {
+func (mr *MockIControllerServiceMockRecorder) RegisterAgent(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAgent", reflect.TypeOf((*MockIControllerService)(nil).RegisterAgent), ctx, payload)
+}
+
+// ReregisterAgent mocks base method.
+func (m *MockIControllerService) ReregisterAgent(ctx context.Context, agentID uuid.UUID, payload request.RegisterAgentRequest) (*response.ConfigResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReregisterAgent", ctx, agentID, payload)
+	ret0, _ := ret[0].(*response.ConfigResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReregisterAgent indicates an expected call of ReregisterAgent.
+func (mr *MockIControllerServiceMockRecorder) ReregisterAgent(ctx, agentID, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReregisterAgent", reflect.TypeOf((*MockIControllerService)(nil).ReregisterAgent), ctx, agentID, payload)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockIControllerService) RevokeAPIKey(ctx context.Context, id uuid.UUID) (*response.APIKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(*response.APIKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockIControllerServiceMockRecorder) RevokeAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockIControllerService)(nil).RevokeAPIKey), ctx, id)
+}
+
+// RollbackConfig mocks base method.
+func (m *MockIControllerService) RollbackConfig(ctx context.Context, namespace string, version int64) (*response.ConfigVersionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackConfig", ctx, namespace, version)
+	ret0, _ := ret[0].(*response.ConfigVersionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackConfig indicates an expected call of RollbackConfig.
+func (mr *MockIControllerServiceMockRecorder) RollbackConfig(ctx, namespace, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackConfig", reflect.TypeOf((*MockIControllerService)(nil).RollbackConfig), ctx, namespace, version)
+}
+
+// SetAgentConfigOverride mocks base method.
+func (m *MockIControllerService) SetAgentConfigOverride(ctx context.Context, agentID uuid.UUID, payload request.AgentConfigOverrideRequest) (*response.AgentConfigOverrideResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAgentConfigOverride", ctx, agentID, payload)
+	ret0, _ := ret[0].(*response.AgentConfigOverrideResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAgentConfigOverride indicates an expected call of SetAgentConfigOverride.
+func (mr *MockIControllerServiceMockRecorder) SetAgentConfigOverride(ctx, agentID, payload interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAgent", reflect.TypeOf((*MockIControllerService)(nil).RegisterAgent), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAgentConfigOverride", reflect.TypeOf((*MockIControllerService)(nil).SetAgentConfigOverride), ctx, agentID, payload)
+}
+
+// SetConfigSchema mocks base method.
+func (m *MockIControllerService) SetConfigSchema(ctx context.Context, namespace string, schema json.RawMessage) (*response.ConfigSchemaResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetConfigSchema", ctx, namespace, schema)
+	ret0, _ := ret[0].(*response.ConfigSchemaResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetConfigSchema indicates an expected call of SetConfigSchema.
+func (mr *MockIControllerServiceMockRecorder) SetConfigSchema(ctx, namespace, schema interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConfigSchema", reflect.TypeOf((*MockIControllerService)(nil).SetConfigSchema), ctx, namespace, schema)
 }
 
 // UpdateConfig mocks base method.
-func (m *MockIControllerService) UpdateConfig(ctx context.Context, payload request.UpdateConfigRequest) error {
+func (m *MockIControllerService) UpdateConfig(ctx context.Context, namespace string, payload request.UpdateConfigRequest) (*response.ConfigUpdateResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateConfig", ctx, payload)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateConfig", ctx, namespace, payload)
+	ret0, _ := ret[0].(*response.ConfigUpdateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateConfig indicates an expected call of UpdateConfig.
-func (mr *MockIControllerServiceMockRecorder) UpdateConfig(ctx, payload interface{}) *gomock.Call {
+func (mr *MockIControllerServiceMockRecorder) UpdateConfig(ctx, namespace, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockIControllerService)(nil).UpdateConfig), ctx, namespace, payload)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockIControllerService) UpdateWebhook(ctx context.Context, id uuid.UUID, payload request.WebhookRequest) (*response.WebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, id, payload)
+	ret0, _ := ret[0].(*response.WebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockIControllerServiceMockRecorder) UpdateWebhook(ctx, id, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockIControllerService)(nil).UpdateWebhook), ctx, id, payload)
+}
+
+// WatchConfig mocks base method.
+func (m *MockIControllerService) WatchConfig(ctx context.Context, namespace, agentID string, sinceVersion int) (*response.ConfigResponse, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchConfig", ctx, namespace, agentID, sinceVersion)
+	ret0, _ := ret[0].(*response.ConfigResponse)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// WatchConfig indicates an expected call of WatchConfig.
+func (mr *MockIControllerServiceMockRecorder) WatchConfig(ctx, namespace, agentID, sinceVersion interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockIControllerService)(nil).UpdateConfig), ctx, payload)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchConfig", reflect.TypeOf((*MockIControllerService)(nil).WatchConfig), ctx, namespace, agentID, sinceVersion)
 }
@@ -0,0 +1,730 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	"controller-service/internal/repository"
+	queries "controller-service/internal/repository/sqlc"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// approvalRequired reports whether updates of namespace need a second key
+// holder's approval.
+func (s *ControllerService) approvalRequired(namespace string) bool {
+	return slices.Contains(s.ApprovalNamespaces, namespace)
+}
+
+// rollbackProposal is the request of a rollback proposal. Config is the
+// document of the target version, kept for reviewers.
+type rollbackProposal struct {
+	Version int64           `json:"version"`
+	Config  json.RawMessage `json:"config"`
+}
+
+// overrideProposal is the request of an override proposal: the override as
+// sent to PUT /agents/{id}/config-override, none to clear it.
+type overrideProposal struct {
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// schemaProposal is the request of a schema proposal.
+type schemaProposal struct {
+	Schema json.RawMessage `json:"schema"`
+}
+
+// rolloutProposal is the request of a promote or abort proposal, Percentage
+// being what a promotion widens the rollout to. Config is the document of
+// the version, kept for reviewers.
+type rolloutProposal struct {
+	Version    int64           `json:"version"`
+	Percentage int             `json:"percentage,omitempty"`
+	Config     json.RawMessage `json:"config"`
+}
+
+// cancelProposal is the request of a cancel proposal. Config and
+// EffectiveAt are those of the scheduled config, kept for reviewers.
+type cancelProposal struct {
+	ScheduledID uuid.UUID       `json:"scheduled_id"`
+	Config      json.RawMessage `json:"config"`
+	EffectiveAt time.Time       `json:"effective_at"`
+}
+
+// createConfigProposal stores a change of the given kind for
+// ApproveConfigProposal to replay and records it in the audit log.
+func (s *ControllerService) createConfigProposal(ctx context.Context, queryTx *queries.Queries, namespace, kind string, agentID uuid.NullUUID, payload any) (*response.ConfigProposalResponse, error) {
+	requestJSON, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("createConfigProposal Failed to marshal proposal request", slog.Any("error", err))
+		return nil, err
+	}
+
+	proposal, err := queryTx.CreateConfigProposal(ctx, queries.CreateConfigProposalParams{
+		Namespace:  namespace,
+		Kind:       kind,
+		AgentID:    agentID,
+		Request:    requestJSON,
+		ProposedBy: auditActor(ctx),
+		ExpiresAt:  time.Now().UTC().Add(s.ProposalTTL),
+	})
+	if err != nil {
+		slog.Error("createConfigProposal Failed to create config proposal", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	proposalResp := toConfigProposalResponse(proposal)
+	if err := recordAudit(ctx, queryTx, response.AuditActionConfigPropose, configProposalResource(namespace, proposal.ID), nil, proposalResp); err != nil {
+		slog.Error("createConfigProposal Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &proposalResp, nil
+}
+
+// proposeConfig stores an update as a proposal for ApproveConfigProposal to
+// replay. The update is checked now as well, so a proposal that could not be
+// applied is rejected before anyone reviews it.
+func (s *ControllerService) proposeConfig(ctx context.Context, queryTx *queries.Queries, namespace string, payload request.UpdateConfigRequest) (*response.ConfigUpdateResponse, error) {
+	previousGlobalConfig, err := checkConfigUpdate(ctx, queryTx, namespace, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal, err := s.createConfigProposal(ctx, queryTx, namespace, response.ConfigProposalKindUpdate, uuid.NullUUID{}, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var currentVersion int64
+	if previousGlobalConfig != nil {
+		currentVersion = previousGlobalConfig.Version
+	}
+
+	return &response.ConfigUpdateResponse{
+		Namespace: namespace,
+		Version:   currentVersion,
+		Status:    response.ConfigStatusProposed,
+		ETag:      response.ETag(currentVersion),
+		Proposal:  proposal,
+	}, nil
+}
+
+// proposeRollback stores a rollback as a proposal for ApproveConfigProposal
+// to replay. The target version must exist now as well.
+func (s *ControllerService) proposeRollback(ctx context.Context, queryTx *queries.Queries, namespace string, version int64) (*response.ConfigVersionResponse, error) {
+	targetGlobalConfig, err := rollbackTarget(ctx, queryTx, namespace, version)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal, err := s.createConfigProposal(ctx, queryTx, namespace, response.ConfigProposalKindRollback, uuid.NullUUID{}, rollbackProposal{
+		Version: version,
+		Config:  targetGlobalConfig.Config,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	previousGlobalConfig, err := currentGlobalConfig(ctx, queryTx, namespace)
+	if err != nil {
+		slog.Error("proposeRollback Failed to fetch current global config", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	resp := response.ConfigVersionResponse{Namespace: namespace}
+	if previousGlobalConfig != nil {
+		resp = toConfigVersionResponse(*previousGlobalConfig)
+	}
+	resp.Status = response.ConfigStatusProposed
+	resp.Proposal = proposal
+	return &resp, nil
+}
+
+// proposeAgentConfigOverride stores an override change as a proposal of the
+// agent's namespace for ApproveConfigProposal to replay. The override is
+// checked now as well.
+func (s *ControllerService) proposeAgentConfigOverride(ctx context.Context, queryTx *queries.Queries, agent queries.Agent, payload request.AgentConfigOverrideRequest) (*response.AgentConfigOverrideResponse, error) {
+	if err := checkAgentConfigOverride(ctx, queryTx, agent, payload); err != nil {
+		return nil, err
+	}
+
+	proposal, err := s.createConfigProposal(ctx, queryTx, agent.Namespace, response.ConfigProposalKindOverride, uuid.NullUUID{UUID: agent.ID, Valid: true}, overrideProposal{
+		Config: payload.Config,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &response.AgentConfigOverrideResponse{
+		AgentID: agent.ID.String(),
+		Config:  json.RawMessage("{}"),
+	}
+	override, err := queryTx.GetAgentConfigOverride(ctx, agent.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		slog.Error("proposeAgentConfigOverride Failed to fetch agent config override", slog.Any("error", err), slog.String("agent_id", agent.ID.String()))
+		return nil, err
+	default:
+		resp = toAgentConfigOverrideResponse(override)
+	}
+	resp.Proposal = proposal
+	return resp, nil
+}
+
+// proposeConfigSchema stores a schema replacement as a proposal for
+// ApproveConfigProposal to replay. The schema must compile now as well.
+func (s *ControllerService) proposeConfigSchema(ctx context.Context, queryTx *queries.Queries, namespace string, schema json.RawMessage) (*response.ConfigSchemaResponse, error) {
+	if _, err := compileSchema(schema); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSchema, err)
+	}
+
+	proposal, err := s.createConfigProposal(ctx, queryTx, namespace, response.ConfigProposalKindSchema, uuid.NullUUID{}, schemaProposal{
+		Schema: schema,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &response.ConfigSchemaResponse{Namespace: namespace}
+	current, err := queryTx.GetConfigSchema(ctx, namespace)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		slog.Error("proposeConfigSchema Failed to fetch config schema", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	default:
+		resp = toConfigSchemaResponse(current)
+	}
+	resp.Proposal = proposal
+	return resp, nil
+}
+
+// proposeConfigRollout stores a promotion or abort, kind telling which, as a
+// proposal for ApproveConfigProposal to replay. The version must be rolling
+// out now as well.
+func (s *ControllerService) proposeConfigRollout(ctx context.Context, queryTx *queries.Queries, namespace string, version int64, percentage int, kind string) (*response.ConfigVersionResponse, error) {
+	target, err := rolloutTarget(ctx, queryTx, namespace, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == response.ConfigProposalKindPromote {
+		_, percentage = promotedRollout(percentage)
+	}
+
+	proposal, err := s.createConfigProposal(ctx, queryTx, namespace, kind, uuid.NullUUID{}, rolloutProposal{
+		Version:    version,
+		Percentage: percentage,
+		Config:     target.Config,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toConfigVersionResponse(*target)
+	resp.Status = response.ConfigStatusProposed
+	resp.Proposal = proposal
+	return &resp, nil
+}
+
+// proposeCancelScheduledConfig stores the cancellation of a scheduled config
+// as a proposal for ApproveConfigProposal to replay. The scheduled config
+// must exist now as well.
+func (s *ControllerService) proposeCancelScheduledConfig(ctx context.Context, queryTx *queries.Queries, namespace string, id uuid.UUID) (*response.ScheduledConfigResponse, error) {
+	scheduled, err := queryTx.GetScheduledConfig(ctx, queries.GetScheduledConfigParams{
+		Namespace: namespace,
+		ID:        id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("proposeCancelScheduledConfig Failed to fetch scheduled config", slog.Any("error", err), slog.String("id", id.String()))
+		return nil, err
+	}
+
+	proposal, err := s.createConfigProposal(ctx, queryTx, namespace, response.ConfigProposalKindCancel, uuid.NullUUID{}, cancelProposal{
+		ScheduledID: id,
+		Config:      scheduled.Config,
+		EffectiveAt: scheduled.EffectiveAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toScheduledConfigResponse(scheduled)
+	resp.Proposal = proposal
+	return &resp, nil
+}
+
+// ListConfigProposals returns the proposals of a namespace, newest first.
+func (s *ControllerService) ListConfigProposals(ctx context.Context, namespace string, filter request.ConfigProposalFilter) (*response.ConfigProposalListResponse, error) {
+	proposals, err := s.Repo.ListConfigProposals(ctx, queries.ListConfigProposalsParams{
+		Namespace: namespace,
+		Status:    sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+	})
+	if err != nil {
+		slog.Error("ListConfigProposals Failed to list config proposals", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	items := make([]response.ConfigProposalResponse, 0, len(proposals))
+	for _, proposal := range proposals {
+		items = append(items, toConfigProposalResponse(proposal))
+	}
+
+	return &response.ConfigProposalListResponse{Items: items}, nil
+}
+
+// ApproveConfigProposal applies a pending proposal as the request it was
+// made from would, in the same transaction that marks it approved. The
+// caller must not be the key that proposed it nor a key related to it, see
+// relatedKeys. A change that no longer applies, such as an update whose
+// expected version is stale, fails and leaves the proposal pending.
+func (s *ControllerService) ApproveConfigProposal(ctx context.Context, namespace string, id uuid.UUID) (*response.ConfigUpdateResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("ApproveConfigProposal Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	proposal, err := queryTx.GetConfigProposalForUpdate(ctx, queries.GetConfigProposalForUpdateParams{
+		Namespace: namespace,
+		ID:        id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("ApproveConfigProposal Failed to fetch config proposal", slog.Any("error", err), slog.String("id", id.String()))
+		return nil, err
+	}
+
+	switch {
+	case proposal.Status != response.ConfigProposalStatusPending:
+		return nil, ErrProposalNotPending
+	case !time.Now().Before(proposal.ExpiresAt):
+		return nil, ErrProposalExpired
+	}
+
+	approver := auditActor(ctx)
+	related, err := relatedKeys(ctx, queryTx, proposal.ProposedBy, approver)
+	if err != nil {
+		return nil, err
+	}
+	if related {
+		return nil, ErrSelfApproval
+	}
+
+	var resp *response.ConfigUpdateResponse
+	switch proposal.Kind {
+	case response.ConfigProposalKindRollback:
+		resp, err = s.approveRollback(ctx, queryTx, proposal)
+	case response.ConfigProposalKindOverride:
+		resp, err = s.approveAgentConfigOverride(ctx, queryTx, proposal)
+	case response.ConfigProposalKindSchema:
+		resp, err = approveConfigSchema(ctx, queryTx, proposal)
+	case response.ConfigProposalKindPromote, response.ConfigProposalKindAbort:
+		resp, err = approveConfigRollout(ctx, queryTx, proposal)
+	case response.ConfigProposalKindCancel:
+		resp, err = approveCancelScheduledConfig(ctx, queryTx, proposal)
+	default:
+		var payload request.UpdateConfigRequest
+		if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+			slog.Error("ApproveConfigProposal Failed to unmarshal update request", slog.Any("error", err), slog.String("id", id.String()))
+			return nil, err
+		}
+		resp, err = s.updateConfig(ctx, queryTx, namespace, payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// schema changes and cancellations create no version, nor do scheduled
+	// updates until they are activated
+	version := resp.Version
+	switch {
+	case resp.Override != nil:
+		version = resp.Override.Version
+	case resp.ScheduledID != "", resp.Schema != nil, resp.Cancelled != nil:
+		version = 0
+	}
+
+	approved, err := queryTx.ApproveConfigProposal(ctx, queries.ApproveConfigProposalParams{
+		ID:         proposal.ID,
+		ApprovedBy: approver,
+		Version:    version,
+	})
+	if err != nil {
+		slog.Error("ApproveConfigProposal Failed to approve config proposal", slog.Any("error", err), slog.String("id", id.String()))
+		return nil, err
+	}
+
+	proposalResp := toConfigProposalResponse(approved)
+	if err := recordAudit(ctx, queryTx, response.AuditActionConfigApprove, configProposalResource(namespace, proposal.ID), toConfigProposalResponse(proposal), proposalResp); err != nil {
+		slog.Error("ApproveConfigProposal Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("ApproveConfigProposal Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	resp.Proposal = &proposalResp
+	return resp, nil
+}
+
+// approveRollback rolls back to the target version of a rollback proposal.
+func (s *ControllerService) approveRollback(ctx context.Context, queryTx *queries.Queries, proposal queries.ConfigProposal) (*response.ConfigUpdateResponse, error) {
+	var payload rollbackProposal
+	if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+		slog.Error("approveRollback Failed to unmarshal rollback request", slog.Any("error", err), slog.String("id", proposal.ID.String()))
+		return nil, err
+	}
+
+	rolledBack, err := s.rollbackConfig(ctx, queryTx, proposal.Namespace, payload.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.ConfigUpdateResponse{
+		Namespace: rolledBack.Namespace,
+		Version:   rolledBack.Version,
+		Status:    rolledBack.Status,
+		ETag:      response.ETag(rolledBack.Version),
+	}, nil
+}
+
+// approveAgentConfigOverride sets the override of an override proposal. The
+// agent must still be in the namespace the proposal was approved in.
+func (s *ControllerService) approveAgentConfigOverride(ctx context.Context, queryTx *queries.Queries, proposal queries.ConfigProposal) (*response.ConfigUpdateResponse, error) {
+	var payload overrideProposal
+	if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+		slog.Error("approveAgentConfigOverride Failed to unmarshal override request", slog.Any("error", err), slog.String("id", proposal.ID.String()))
+		return nil, err
+	}
+
+	agent, err := queryTx.GetAgent(ctx, proposal.AgentID.UUID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && agent.Namespace != proposal.Namespace) {
+		return nil, ErrProposalAgentGone
+	}
+	if err != nil {
+		slog.Error("approveAgentConfigOverride Failed to fetch agent", slog.Any("error", err), slog.String("agent_id", proposal.AgentID.UUID.String()))
+		return nil, err
+	}
+
+	override, err := s.setAgentConfigOverride(ctx, queryTx, agent, request.AgentConfigOverrideRequest{Config: payload.Config})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := currentConfigUpdateResponse(ctx, queryTx, proposal.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	resp.Override = override
+	return resp, nil
+}
+
+// approveConfigSchema sets the schema of a schema proposal.
+func approveConfigSchema(ctx context.Context, queryTx *queries.Queries, proposal queries.ConfigProposal) (*response.ConfigUpdateResponse, error) {
+	var payload schemaProposal
+	if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+		slog.Error("approveConfigSchema Failed to unmarshal schema request", slog.Any("error", err), slog.String("id", proposal.ID.String()))
+		return nil, err
+	}
+
+	schema, err := setConfigSchema(ctx, queryTx, proposal.Namespace, payload.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := currentConfigUpdateResponse(ctx, queryTx, proposal.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	resp.Schema = schema
+	return resp, nil
+}
+
+// approveConfigRollout promotes or aborts the version of a promote or abort
+// proposal, which must still be rolling out.
+func approveConfigRollout(ctx context.Context, queryTx *queries.Queries, proposal queries.ConfigProposal) (*response.ConfigUpdateResponse, error) {
+	var payload rolloutProposal
+	if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+		slog.Error("approveConfigRollout Failed to unmarshal rollout request", slog.Any("error", err), slog.String("id", proposal.ID.String()))
+		return nil, err
+	}
+
+	updated, err := updateConfigRollout(ctx, queryTx, proposal.Namespace, payload.Version, payload.Percentage, proposal.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.ConfigUpdateResponse{
+		Namespace: updated.Namespace,
+		Version:   updated.Version,
+		Status:    updated.Status,
+		ETag:      response.ETag(updated.Version),
+	}, nil
+}
+
+// approveCancelScheduledConfig deletes the scheduled config of a cancel
+// proposal, which must not have been activated or cancelled since.
+func approveCancelScheduledConfig(ctx context.Context, queryTx *queries.Queries, proposal queries.ConfigProposal) (*response.ConfigUpdateResponse, error) {
+	var payload cancelProposal
+	if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+		slog.Error("approveCancelScheduledConfig Failed to unmarshal cancel request", slog.Any("error", err), slog.String("id", proposal.ID.String()))
+		return nil, err
+	}
+
+	cancelled, err := cancelScheduledConfig(ctx, queryTx, proposal.Namespace, payload.ScheduledID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrProposalScheduleGone
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := currentConfigUpdateResponse(ctx, queryTx, proposal.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	resp.Cancelled = cancelled
+	return resp, nil
+}
+
+// currentConfigUpdateResponse describes the current version of the
+// namespace, for approvals that create none.
+func currentConfigUpdateResponse(ctx context.Context, queryTx *queries.Queries, namespace string) (*response.ConfigUpdateResponse, error) {
+	current, err := currentGlobalConfig(ctx, queryTx, namespace)
+	if err != nil {
+		slog.Error("currentConfigUpdateResponse Failed to fetch current global config", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	resp := &response.ConfigUpdateResponse{Namespace: namespace}
+	if current != nil {
+		resp.Version = current.Version
+		resp.Status = current.Status
+	}
+	resp.ETag = response.ETag(resp.Version)
+	return resp, nil
+}
+
+// relatedKeys reports whether approver may be acting for proposer: the same
+// key, keys of which one created the other, directly or through other keys,
+// or keys created by the same key. Keys created by the bootstrap key are not
+// related through it, as it issues the first key of every holder.
+func relatedKeys(ctx context.Context, repo repository.IRepository, proposer, approver string) (bool, error) {
+	if proposer == approver {
+		return true, nil
+	}
+
+	proposerCreators, err := keyCreators(ctx, repo, proposer)
+	if err != nil {
+		return false, err
+	}
+	approverCreators, err := keyCreators(ctx, repo, approver)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case slices.Contains(proposerCreators, approver), slices.Contains(approverCreators, proposer):
+		return true, nil
+	case len(proposerCreators) > 0 && len(approverCreators) > 0:
+		creator := proposerCreators[0]
+		return creator == approverCreators[0] && creator != apiKeyActorPrefix+request.BootstrapAPIKeyName, nil
+	}
+	return false, nil
+}
+
+// keyCreators returns the actors that created the key of actor, its creator
+// first, then its creator's and so on. The chain ends at the bootstrap key,
+// which is not stored, or at a key created before creators were recorded.
+func keyCreators(ctx context.Context, repo repository.IRepository, actor string) ([]string, error) {
+	var creators []string
+	for {
+		name, ok := strings.CutPrefix(actor, apiKeyActorPrefix)
+		if !ok {
+			return creators, nil
+		}
+
+		apiKey, err := repo.GetAPIKeyByName(ctx, name)
+		if errors.Is(err, sql.ErrNoRows) {
+			return creators, nil
+		}
+		if err != nil {
+			slog.Error("keyCreators Failed to fetch api key", slog.Any("error", err), slog.String("name", name))
+			return nil, err
+		}
+
+		actor = apiKey.CreatedBy
+		if actor == "" || slices.Contains(creators, actor) {
+			return creators, nil
+		}
+		creators = append(creators, actor)
+	}
+}
+
+// RunProposalExpirer marks pending proposals past their expiry as expired,
+// once per interval until ctx is done. Approvals check the expiry
+// themselves, so this only keeps listings and the audit log current.
+func (s *ControllerService) RunProposalExpirer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.expireConfigProposals(ctx); err != nil {
+			slog.Error("RunProposalExpirer Failed to expire config proposals", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ControllerService) expireConfigProposals(ctx context.Context) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	expired, err := queryTx.ExpireConfigProposals(ctx, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for _, proposal := range expired {
+		before := toConfigProposalResponse(proposal)
+		before.Status = response.ConfigProposalStatusPending
+		before.DecidedAt = nil
+		if err := recordAudit(ctx, queryTx, response.AuditActionConfigExpire, configProposalResource(proposal.Namespace, proposal.ID), before, toConfigProposalResponse(proposal)); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if len(expired) > 0 {
+		slog.Info("RunProposalExpirer expired config proposals", slog.Int("count", len(expired)))
+	}
+	return nil
+}
+
+func configProposalResource(namespace string, id uuid.UUID) string {
+	return configResource(namespace) + "/proposals/" + id.String()
+}
+
+func toConfigProposalResponse(proposal queries.ConfigProposal) response.ConfigProposalResponse {
+	resp := response.ConfigProposalResponse{
+		ID:         proposal.ID.String(),
+		Namespace:  proposal.Namespace,
+		Kind:       proposal.Kind,
+		Status:     proposal.Status,
+		Config:     proposal.Request,
+		ProposedBy: proposal.ProposedBy,
+		ApprovedBy: proposal.ApprovedBy,
+		Version:    proposal.Version,
+		ExpiresAt:  proposal.ExpiresAt,
+		CreatedAt:  proposal.CreatedAt,
+	}
+	if proposal.DecidedAt.Valid {
+		resp.DecidedAt = &proposal.DecidedAt.Time
+	}
+
+	switch proposal.Kind {
+	case response.ConfigProposalKindRollback:
+		var payload rollbackProposal
+		if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+			slog.Warn("toConfigProposalResponse Failed to unmarshal rollback request", slog.Any("error", err), slog.String("id", resp.ID))
+			return resp
+		}
+		resp.Config = payload.Config
+		resp.RollbackVersion = payload.Version
+		return resp
+	case response.ConfigProposalKindOverride:
+		var payload overrideProposal
+		if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+			slog.Warn("toConfigProposalResponse Failed to unmarshal override request", slog.Any("error", err), slog.String("id", resp.ID))
+			return resp
+		}
+		// clearing the override serves the namespace config as is
+		resp.Config = json.RawMessage("{}")
+		if len(payload.Config) > 0 {
+			resp.Config = payload.Config
+		}
+		resp.AgentID = proposal.AgentID.UUID.String()
+		return resp
+	case response.ConfigProposalKindSchema:
+		var payload schemaProposal
+		if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+			slog.Warn("toConfigProposalResponse Failed to unmarshal schema request", slog.Any("error", err), slog.String("id", resp.ID))
+			return resp
+		}
+		resp.Config = payload.Schema
+		return resp
+	case response.ConfigProposalKindPromote, response.ConfigProposalKindAbort:
+		var payload rolloutProposal
+		if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+			slog.Warn("toConfigProposalResponse Failed to unmarshal rollout request", slog.Any("error", err), slog.String("id", resp.ID))
+			return resp
+		}
+		resp.Config = payload.Config
+		resp.TargetVersion = payload.Version
+		if proposal.Kind == response.ConfigProposalKindPromote {
+			resp.Rollout = &response.RolloutPolicy{Percentage: payload.Percentage}
+		}
+		return resp
+	case response.ConfigProposalKindCancel:
+		var payload cancelProposal
+		if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+			slog.Warn("toConfigProposalResponse Failed to unmarshal cancel request", slog.Any("error", err), slog.String("id", resp.ID))
+			return resp
+		}
+		resp.Config = payload.Config
+		resp.ScheduledID = payload.ScheduledID.String()
+		resp.EffectiveAt = &payload.EffectiveAt
+		return resp
+	}
+
+	var payload request.UpdateConfigRequest
+	if err := json.Unmarshal(proposal.Request, &payload); err != nil {
+		slog.Warn("toConfigProposalResponse Failed to unmarshal update request", slog.Any("error", err), slog.String("id", resp.ID))
+		return resp
+	}
+
+	resp.Config = payload.Config
+	resp.ExpectedVersion = payload.ExpectedVersion
+	resp.EffectiveAt = payload.EffectiveAt
+	if payload.Rollout != nil {
+		resp.Rollout = &response.RolloutPolicy{
+			Percentage: payload.Rollout.Percentage,
+			Selector:   payload.Rollout.Selector,
+		}
+	}
+	return resp
+}
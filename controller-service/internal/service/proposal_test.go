@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/response"
+	"controller-service/internal/repository"
+	queries "controller-service/internal/repository/sqlc"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestToConfigProposalResponse(t *testing.T) {
+	agentID := uuid.New()
+	scheduledID := uuid.New()
+
+	tests := []struct {
+		name                string
+		kind                string
+		agentID             uuid.NullUUID
+		request             string
+		wantConfig          string
+		wantRollbackVersion int64
+		wantAgentID         string
+		wantTargetVersion   int64
+		wantScheduledID     string
+	}{
+		{
+			name:       "update",
+			kind:       response.ConfigProposalKindUpdate,
+			request:    `{"url":"https://v8","expected_version":7}`,
+			wantConfig: `{"url":"https://v8"}`,
+		},
+		{
+			name:                "rollback",
+			kind:                response.ConfigProposalKindRollback,
+			request:             `{"version":3,"config":{"url":"https://v3"}}`,
+			wantConfig:          `{"url":"https://v3"}`,
+			wantRollbackVersion: 3,
+		},
+		{
+			name:        "override",
+			kind:        response.ConfigProposalKindOverride,
+			agentID:     uuid.NullUUID{UUID: agentID, Valid: true},
+			request:     `{"config":{"url":"https://canary"}}`,
+			wantConfig:  `{"url":"https://canary"}`,
+			wantAgentID: agentID.String(),
+		},
+		{
+			name:        "override cleared",
+			kind:        response.ConfigProposalKindOverride,
+			agentID:     uuid.NullUUID{UUID: agentID, Valid: true},
+			request:     `{}`,
+			wantConfig:  `{}`,
+			wantAgentID: agentID.String(),
+		},
+		{
+			name:       "schema",
+			kind:       response.ConfigProposalKindSchema,
+			request:    `{"schema":{"type":"object"}}`,
+			wantConfig: `{"type":"object"}`,
+		},
+		{
+			name:              "promote",
+			kind:              response.ConfigProposalKindPromote,
+			request:           `{"version":5,"percentage":50,"config":{"url":"https://v5"}}`,
+			wantConfig:        `{"url":"https://v5"}`,
+			wantTargetVersion: 5,
+		},
+		{
+			name:              "abort",
+			kind:              response.ConfigProposalKindAbort,
+			request:           `{"version":5,"config":{"url":"https://v5"}}`,
+			wantConfig:        `{"url":"https://v5"}`,
+			wantTargetVersion: 5,
+		},
+		{
+			name:            "cancel",
+			kind:            response.ConfigProposalKindCancel,
+			request:         `{"scheduled_id":"` + scheduledID.String() + `","config":{"url":"https://night"},"effective_at":"2026-03-12T02:00:00Z"}`,
+			wantConfig:      `{"url":"https://night"}`,
+			wantScheduledID: scheduledID.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := toConfigProposalResponse(queries.ConfigProposal{
+				ID:        uuid.New(),
+				Namespace: "prod",
+				Kind:      tt.kind,
+				AgentID:   tt.agentID,
+				Request:   json.RawMessage(tt.request),
+				Status:    response.ConfigProposalStatusPending,
+			})
+
+			if resp.Kind != tt.kind {
+				t.Errorf("Kind = %q, want %q", resp.Kind, tt.kind)
+			}
+			if string(resp.Config) != tt.wantConfig {
+				t.Errorf("Config = %s, want %s", resp.Config, tt.wantConfig)
+			}
+			if resp.RollbackVersion != tt.wantRollbackVersion {
+				t.Errorf("RollbackVersion = %d, want %d", resp.RollbackVersion, tt.wantRollbackVersion)
+			}
+			if resp.AgentID != tt.wantAgentID {
+				t.Errorf("AgentID = %q, want %q", resp.AgentID, tt.wantAgentID)
+			}
+			if resp.TargetVersion != tt.wantTargetVersion {
+				t.Errorf("TargetVersion = %d, want %d", resp.TargetVersion, tt.wantTargetVersion)
+			}
+			if resp.ScheduledID != tt.wantScheduledID {
+				t.Errorf("ScheduledID = %q, want %q", resp.ScheduledID, tt.wantScheduledID)
+			}
+		})
+	}
+}
+
+// keyRepo holds API keys by name, mapped to the actor that created them.
+// Any other query panics through the nil embedded IRepository.
+type keyRepo struct {
+	repository.IRepository
+
+	createdBy map[string]string
+}
+
+func (r *keyRepo) GetAPIKeyByName(ctx context.Context, name string) (queries.ApiKey, error) {
+	createdBy, ok := r.createdBy[name]
+	if !ok {
+		return queries.ApiKey{}, sql.ErrNoRows
+	}
+	return queries.ApiKey{Name: name, CreatedBy: createdBy}, nil
+}
+
+func TestRelatedKeys(t *testing.T) {
+	repo := &keyRepo{createdBy: map[string]string{
+		"alice":     "api-key:bootstrap",
+		"bob":       "api-key:bootstrap",
+		"alice-ci":  "api-key:alice",
+		"alice-bot": "api-key:alice-ci",
+		"alice-ops": "api-key:alice",
+		"bob-ci":    "api-key:bob",
+		"legacy":    "",
+	}}
+
+	tests := []struct {
+		name     string
+		proposer string
+		approver string
+		want     bool
+	}{
+		{name: "same key", proposer: "api-key:alice", approver: "api-key:alice", want: true},
+		{name: "created by the proposer", proposer: "api-key:alice", approver: "api-key:alice-ci", want: true},
+		{name: "created the proposer", proposer: "api-key:alice-ci", approver: "api-key:alice", want: true},
+		{name: "created through another key", proposer: "api-key:alice", approver: "api-key:alice-bot", want: true},
+		{name: "same creator", proposer: "api-key:alice-ci", approver: "api-key:alice-ops", want: true},
+		{name: "bootstrap and its key", proposer: "api-key:bootstrap", approver: "api-key:alice", want: true},
+		{name: "both created by bootstrap", proposer: "api-key:alice", approver: "api-key:bob", want: false},
+		{name: "other holders", proposer: "api-key:alice-ci", approver: "api-key:bob-ci", want: false},
+		{name: "key without creator", proposer: "api-key:legacy", approver: "api-key:alice", want: false},
+		{name: "config sync", proposer: "config-sync:prod.yaml", approver: "api-key:alice", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			related, err := relatedKeys(context.Background(), repo, tt.proposer, tt.approver)
+			if err != nil {
+				t.Fatalf("relatedKeys() error = %v", err)
+			}
+			if related != tt.want {
+				t.Errorf("relatedKeys(%q, %q) = %v, want %v", tt.proposer, tt.approver, related, tt.want)
+			}
+		})
+	}
+}
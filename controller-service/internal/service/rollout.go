@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	queries "controller-service/internal/repository/sqlc"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// GetConfigRollout reports how many agents registered in the namespace have acknowledged the
+// given version through their heartbeats. An agent acknowledges a version once
+// its applied version is equal or newer. For canary and aborted versions only
+// the agents targeted by the rollout are counted.
+//
+// Propagation time is measured from the version's creation to the latest
+// acknowledgement of agents still on that exact version; agents that already
+// moved on to a newer version count as acknowledged but carry no timing.
+func (s *ControllerService) GetConfigRollout(ctx context.Context, namespace string, version int64) (*response.ConfigRolloutResponse, error) {
+	globalConfig, err := s.Repo.GetGlobalConfigByVersion(ctx, queries.GetGlobalConfigByVersionParams{
+		Namespace: namespace,
+		Version:   version,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("GetConfigRollout Failed to fetch global config", slog.Any("error", err), slog.Int64("version", version))
+		return nil, err
+	}
+
+	agents, err := s.Repo.ListAgentsByNamespace(ctx, namespace)
+	if err != nil {
+		slog.Error("GetConfigRollout Failed to list agents", slog.Any("error", err))
+		return nil, err
+	}
+
+	resp := &response.ConfigRolloutResponse{
+		Namespace:     namespace,
+		Version:       globalConfig.Version,
+		Status:        globalConfig.Status,
+		CreatedAt:     globalConfig.CreatedAt,
+		LaggingAgents: []response.RolloutAgent{},
+	}
+
+	for _, agent := range agents {
+		// agents shut down on purpose do not hold a rollout back
+		if agent.DeregisteredAt.Valid {
+			continue
+		}
+
+		if globalConfig.Status != response.ConfigStatusActive {
+			selected, err := inRollout(globalConfig, agent)
+			if err != nil {
+				slog.Error("GetConfigRollout Failed to evaluate rollout", slog.Any("error", err), slog.String("agent_id", agent.ID.String()))
+				return nil, err
+			}
+			if !selected {
+				continue
+			}
+		}
+
+		resp.TotalAgents++
+
+		if !agent.AppliedVersion.Valid || agent.AppliedVersion.Int64 < version {
+			lagging := response.RolloutAgent{
+				ID:     agent.ID.String(),
+				Name:   agent.Name,
+				Status: s.agentStatus(agent),
+			}
+			if agent.AppliedVersion.Valid {
+				lagging.AppliedVersion = &agent.AppliedVersion.Int64
+			}
+			if agent.LastSeenAt.Valid {
+				lagging.LastSeenAt = &agent.LastSeenAt.Time
+			}
+			resp.LaggingAgents = append(resp.LaggingAgents, lagging)
+			continue
+		}
+
+		resp.AcknowledgedAgents++
+
+		if agent.AppliedVersion.Int64 == version && agent.AppliedAt.Valid {
+			propagation := agent.AppliedAt.Time.Sub(globalConfig.CreatedAt).Seconds()
+			if resp.PropagationSeconds == nil || propagation > *resp.PropagationSeconds {
+				resp.PropagationSeconds = &propagation
+			}
+		}
+	}
+
+	resp.Complete = resp.AcknowledgedAgents == resp.TotalAgents
+
+	return resp, nil
+}
+
+// rolloutParams is the rollout state a new config version is created with.
+type rolloutParams struct {
+	status     string
+	percentage int32
+	selector   json.RawMessage
+}
+
+// newRolloutParams turns a rollout policy into the state of a new version: a
+// canary when the policy limits the version to some agents, active otherwise.
+func newRolloutParams(policy *request.RolloutPolicy) (rolloutParams, error) {
+	params := rolloutParams{
+		status:     response.ConfigStatusActive,
+		percentage: 100,
+		selector:   json.RawMessage("{}"),
+	}
+	if policy == nil {
+		return params, nil
+	}
+
+	if policy.Percentage > 0 {
+		params.percentage = int32(policy.Percentage)
+	}
+	if len(policy.Selector) > 0 {
+		selector, err := json.Marshal(policy.Selector)
+		if err != nil {
+			return params, err
+		}
+		params.selector = selector
+	}
+	if params.percentage < 100 || len(policy.Selector) > 0 {
+		params.status = response.ConfigStatusCanary
+	}
+
+	return params, nil
+}
+
+// servedGlobalConfig returns the config version served to the caller: the
+// canary version when the caller is selected by its rollout, otherwise the
+// latest active version.
+func (s *ControllerService) servedGlobalConfig(ctx context.Context, namespace string, caller *queries.Agent) (queries.GlobalConfig, error) {
+	if caller != nil && caller.Namespace == namespace {
+		canary, err := s.Repo.GetLatestCanaryGlobalConfig(ctx, namespace)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return canary, err
+		default:
+			selected, err := inRollout(canary, *caller)
+			if err != nil {
+				return canary, err
+			}
+			if selected {
+				return canary, nil
+			}
+		}
+	}
+
+	return s.Repo.GetLatestVersionGlobalConfig(ctx, namespace)
+}
+
+// inRollout reports whether a canary version targets the agent: the agent's
+// labels must match the rollout selector and its bucket must fall within the
+// rollout percentage. Buckets only depend on the agent ID and the version, so
+// widening the percentage keeps every agent that was already selected.
+func inRollout(config queries.GlobalConfig, agent queries.Agent) (bool, error) {
+	var selector, labels map[string]string
+	if err := json.Unmarshal(config.RolloutSelector, &selector); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(agent.Labels, &labels); err != nil {
+		return false, err
+	}
+
+	for key, value := range selector {
+		if labels[key] != value {
+			return false, nil
+		}
+	}
+
+	return rolloutBucket(agent.ID, config.Version) < int(config.RolloutPercentage), nil
+}
+
+// rolloutBucket maps an agent to one of 100 buckets, salted with the version
+// so that successive canaries do not always land on the same agents.
+func rolloutBucket(agentID uuid.UUID, version int64) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d", agentID, version)
+	return int(h.Sum32() % 100)
+}
+
+// PromoteConfigVersion widens a canary rollout to the given percentage of
+// the selected agents, or makes the version live for every agent of the
+// namespace when percentage is 0 or 100. In namespaces requiring approval
+// the promotion is proposed instead.
+func (s *ControllerService) PromoteConfigVersion(ctx context.Context, namespace string, version int64, percentage int) (*response.ConfigVersionResponse, error) {
+	return s.changeConfigRollout(ctx, namespace, version, percentage, response.ConfigProposalKindPromote)
+}
+
+// AbortConfigVersion cancels a canary rollout; the selected agents go back to
+// the latest active version. In namespaces requiring approval the abort is
+// proposed instead.
+func (s *ControllerService) AbortConfigVersion(ctx context.Context, namespace string, version int64) (*response.ConfigVersionResponse, error) {
+	return s.changeConfigRollout(ctx, namespace, version, 0, response.ConfigProposalKindAbort)
+}
+
+// changeConfigRollout promotes or aborts a rollout, kind telling which, or
+// proposes to.
+func (s *ControllerService) changeConfigRollout(ctx context.Context, namespace string, version int64, percentage int, kind string) (*response.ConfigVersionResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("changeConfigRollout Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	var resp *response.ConfigVersionResponse
+	if s.approvalRequired(namespace) {
+		resp, err = s.proposeConfigRollout(ctx, queryTx, namespace, version, percentage, kind)
+	} else {
+		resp, err = updateConfigRollout(ctx, queryTx, namespace, version, percentage, kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("changeConfigRollout Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// rolloutTarget returns the version a promotion or abort changes, which
+// must be rolling out.
+func rolloutTarget(ctx context.Context, queryTx *queries.Queries, namespace string, version int64) (*queries.GlobalConfig, error) {
+	globalConfig, err := queryTx.GetGlobalConfigByVersion(ctx, queries.GetGlobalConfigByVersionParams{
+		Namespace: namespace,
+		Version:   version,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("rolloutTarget Failed to fetch global config", slog.Any("error", err), slog.Int64("version", version))
+		return nil, err
+	}
+	if globalConfig.Status != response.ConfigStatusCanary {
+		return nil, ErrRolloutNotInProgress
+	}
+
+	return &globalConfig, nil
+}
+
+// promotedRollout returns the status and percentage a promotion to
+// percentage leaves a version with.
+func promotedRollout(percentage int) (string, int) {
+	if percentage == 0 || percentage == 100 {
+		return response.ConfigStatusActive, 100
+	}
+	return response.ConfigStatusCanary, percentage
+}
+
+// updateConfigRollout promotes or aborts a rollout through queryTx, see
+// changeConfigRollout.
+func updateConfigRollout(ctx context.Context, queryTx *queries.Queries, namespace string, version int64, percentage int, kind string) (*response.ConfigVersionResponse, error) {
+	status, auditAction := response.ConfigStatusAborted, response.AuditActionConfigAbort
+	if kind == response.ConfigProposalKindPromote {
+		status, percentage = promotedRollout(percentage)
+		auditAction = response.AuditActionConfigPromote
+	}
+
+	target, err := rolloutTarget(ctx, queryTx, namespace, version)
+	if err != nil {
+		return nil, err
+	}
+	globalConfig := *target
+
+	// the webhook event diffs the rollout against the version served to the
+	// agents it does not select
+	var activeGlobalConfig *queries.GlobalConfig
+	latestGlobalConfig, err := queryTx.GetLatestVersionGlobalConfig(ctx, namespace)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		slog.Error("updateConfigRollout Failed to fetch active global config", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	default:
+		activeGlobalConfig = &latestGlobalConfig
+	}
+
+	// aborted keeps the percentage the rollout reached
+	if status == response.ConfigStatusAborted {
+		percentage = int(globalConfig.RolloutPercentage)
+	}
+
+	updated, err := queryTx.UpdateGlobalConfigRollout(ctx, queries.UpdateGlobalConfigRolloutParams{
+		Namespace:         namespace,
+		Version:           version,
+		Status:            status,
+		RolloutPercentage: int32(percentage),
+	})
+	if err != nil {
+		slog.Error("updateConfigRollout Failed to update global config rollout", slog.Any("error", err), slog.Int64("version", version))
+		return nil, err
+	}
+	if updated == 0 {
+		return nil, ErrRolloutNotInProgress
+	}
+
+	updatedGlobalConfig := globalConfig
+	updatedGlobalConfig.Status = status
+	updatedGlobalConfig.RolloutPercentage = int32(percentage)
+
+	if err := recordAudit(ctx, queryTx, auditAction, configResource(namespace), configAuditState(&globalConfig), configAuditState(&updatedGlobalConfig)); err != nil {
+		slog.Error("updateConfigRollout Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := enqueueConfigEvent(ctx, queryTx, auditAction, activeGlobalConfig, updatedGlobalConfig); err != nil {
+		slog.Error("updateConfigRollout Failed to enqueue webhook event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := queryTx.NotifyGlobalConfigUpdated(ctx, namespace); err != nil {
+		slog.Error("updateConfigRollout Failed to notify global config update", slog.Any("error", err))
+		return nil, err
+	}
+
+	slog.Info("updateConfigRollout updated config rollout", slog.String("namespace", namespace), slog.Int64("version", version), slog.String("status", status), slog.Int("percentage", percentage))
+
+	resp := toConfigVersionResponse(updatedGlobalConfig)
+	return &resp, nil
+}
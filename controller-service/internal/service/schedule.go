@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	queries "controller-service/internal/repository/sqlc"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// scheduleConfig stores an UpdateConfig call with an effective_at as a
+// scheduled config, for RunConfigScheduler to activate. It takes no version
+// until then and nothing served changes, so watchers are not notified.
+func (s *ControllerService) scheduleConfig(ctx context.Context, queryTx *queries.Queries, namespace string, payload request.UpdateConfigRequest, previousGlobalConfig *queries.GlobalConfig) (*response.ConfigUpdateResponse, error) {
+	rollout, err := newRolloutParams(payload.Rollout)
+	if err != nil {
+		slog.Error("scheduleConfig Failed to marshal rollout selector", slog.Any("error", err))
+		return nil, err
+	}
+
+	scheduled, err := queryTx.CreateScheduledConfig(ctx, queries.CreateScheduledConfigParams{
+		Namespace:         namespace,
+		Config:            payload.Config,
+		RolloutPercentage: rollout.percentage,
+		RolloutSelector:   rollout.selector,
+		EffectiveAt:       payload.EffectiveAt.UTC(),
+	})
+	if err != nil {
+		slog.Error("scheduleConfig Failed to create scheduled config", slog.Any("error", err))
+		return nil, err
+	}
+
+	state := toScheduledConfigResponse(scheduled)
+	if err := recordAudit(ctx, queryTx, response.AuditActionConfigSchedule, scheduledConfigResource(namespace, scheduled.ID), nil, &state); err != nil {
+		slog.Error("scheduleConfig Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	var currentVersion int64
+	if previousGlobalConfig != nil {
+		currentVersion = previousGlobalConfig.Version
+	}
+
+	return &response.ConfigUpdateResponse{
+		Namespace:   namespace,
+		Version:     currentVersion,
+		Status:      response.ConfigStatusScheduled,
+		ETag:        response.ETag(currentVersion),
+		ScheduledID: scheduled.ID.String(),
+		EffectiveAt: &scheduled.EffectiveAt,
+	}, nil
+}
+
+// ListScheduledConfigs returns the scheduled configs of a namespace, the
+// next one to be activated first.
+func (s *ControllerService) ListScheduledConfigs(ctx context.Context, namespace string) (*response.ScheduledConfigListResponse, error) {
+	configs, err := s.Repo.ListScheduledConfigs(ctx, namespace)
+	if err != nil {
+		slog.Error("ListScheduledConfigs Failed to list scheduled configs", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	items := make([]response.ScheduledConfigResponse, 0, len(configs))
+	for _, config := range configs {
+		items = append(items, toScheduledConfigResponse(config))
+	}
+
+	return &response.ScheduledConfigListResponse{Items: items}, nil
+}
+
+// CancelScheduledConfig deletes a scheduled config before it is activated,
+// or proposes to in namespaces requiring approval. Activated and unknown
+// ones return ErrNotFound.
+func (s *ControllerService) CancelScheduledConfig(ctx context.Context, namespace string, id uuid.UUID) (*response.ScheduledConfigResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("CancelScheduledConfig Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	var resp *response.ScheduledConfigResponse
+	if s.approvalRequired(namespace) {
+		resp, err = s.proposeCancelScheduledConfig(ctx, queryTx, namespace, id)
+	} else {
+		resp, err = cancelScheduledConfig(ctx, queryTx, namespace, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("CancelScheduledConfig Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// cancelScheduledConfig deletes a scheduled config through queryTx, see
+// CancelScheduledConfig.
+func cancelScheduledConfig(ctx context.Context, queryTx *queries.Queries, namespace string, id uuid.UUID) (*response.ScheduledConfigResponse, error) {
+	scheduled, err := queryTx.DeleteScheduledConfig(ctx, queries.DeleteScheduledConfigParams{
+		Namespace: namespace,
+		ID:        id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("cancelScheduledConfig Failed to delete scheduled config", slog.Any("error", err), slog.String("id", id.String()))
+		return nil, err
+	}
+
+	resp := toScheduledConfigResponse(scheduled)
+	if err := recordAudit(ctx, queryTx, response.AuditActionConfigCancel, scheduledConfigResource(namespace, id), &resp, nil); err != nil {
+		slog.Error("cancelScheduledConfig Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// RunConfigScheduler activates scheduled configs whose effective_at has
+// passed, checking once per interval until ctx is done. Due configs are
+// locked with SKIP LOCKED, so several controllers can run it.
+func (s *ControllerService) RunConfigScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			activated, err := s.activateScheduledConfig(ctx)
+			if err != nil {
+				slog.Error("RunConfigScheduler Failed to activate scheduled config", slog.Any("error", err))
+				break
+			}
+			if !activated {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// activateScheduledConfig commits the earliest due scheduled config as the
+// next version of its namespace, like UpdateConfig would, and reports
+// whether there was one.
+func (s *ControllerService) activateScheduledConfig(ctx context.Context) (bool, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	scheduled, err := queryTx.GetDueScheduledConfig(ctx, time.Now().UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	namespace := scheduled.Namespace
+
+	previousGlobalConfig, err := currentGlobalConfig(ctx, queryTx, namespace)
+	if err != nil {
+		return false, err
+	}
+
+	// the activated version supersedes any canary still rolling out
+	if _, err := queryTx.AbortCanaryGlobalConfigs(ctx, namespace); err != nil {
+		return false, err
+	}
+
+	latestVersion, err := queryTx.GetMaxVersionGlobalConfig(ctx, namespace)
+	if err != nil {
+		return false, err
+	}
+
+	activated, err := queryTx.CreateGlobalConfig(ctx, queries.CreateGlobalConfigParams{
+		Namespace:         namespace,
+		Config:            scheduled.Config,
+		Version:           latestVersion + 1,
+		Status:            rolloutStatus(scheduled.RolloutPercentage, scheduled.RolloutSelector),
+		RolloutPercentage: scheduled.RolloutPercentage,
+		RolloutSelector:   scheduled.RolloutSelector,
+		EffectiveAt:       sql.NullTime{Time: scheduled.EffectiveAt, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := queryTx.DeleteScheduledConfig(ctx, queries.DeleteScheduledConfigParams{
+		Namespace: namespace,
+		ID:        scheduled.ID,
+	}); err != nil {
+		return false, err
+	}
+
+	if err := recordAudit(ctx, queryTx, response.AuditActionConfigActivate, configResource(namespace), configAuditState(previousGlobalConfig), configAuditState(&activated)); err != nil {
+		return false, err
+	}
+
+	if err := enqueueConfigEvent(ctx, queryTx, response.AuditActionConfigActivate, previousGlobalConfig, activated); err != nil {
+		return false, err
+	}
+
+	if err := queryTx.NotifyGlobalConfigUpdated(ctx, namespace); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	slog.Info("RunConfigScheduler activated scheduled config", slog.String("namespace", namespace), slog.String("id", scheduled.ID.String()), slog.Int64("version", activated.Version), slog.String("status", activated.Status))
+	return true, nil
+}
+
+// rolloutStatus is the status of a version with the given rollout: canary
+// when it limits the version to some agents, active otherwise.
+func rolloutStatus(percentage int32, selector json.RawMessage) string {
+	var labels map[string]string
+	if err := json.Unmarshal(selector, &labels); err != nil {
+		slog.Warn("rolloutStatus Failed to unmarshal rollout selector", slog.Any("error", err))
+	}
+	if percentage < 100 || len(labels) > 0 {
+		return response.ConfigStatusCanary
+	}
+	return response.ConfigStatusActive
+}
+
+func scheduledConfigResource(namespace string, id uuid.UUID) string {
+	return configResource(namespace) + "/scheduled/" + id.String()
+}
+
+func toScheduledConfigResponse(config queries.ScheduledConfig) response.ScheduledConfigResponse {
+	resp := response.ScheduledConfigResponse{
+		ID:          config.ID.String(),
+		Namespace:   config.Namespace,
+		Status:      response.ConfigStatusScheduled,
+		Config:      config.Config,
+		EffectiveAt: config.EffectiveAt,
+		CreatedAt:   config.CreatedAt,
+	}
+
+	if rolloutStatus(config.RolloutPercentage, config.RolloutSelector) == response.ConfigStatusCanary {
+		resp.Rollout = &response.RolloutPolicy{Percentage: int(config.RolloutPercentage)}
+		if err := json.Unmarshal(config.RolloutSelector, &resp.Rollout.Selector); err != nil {
+			slog.Warn("toScheduledConfigResponse Failed to unmarshal rollout selector", slog.Any("error", err), slog.String("id", resp.ID))
+		}
+	}
+
+	return resp
+}
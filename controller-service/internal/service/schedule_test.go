@@ -0,0 +1,60 @@
+package service
+
+import (
+	"controller-service/internal/api/response"
+	queries "controller-service/internal/repository/sqlc"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestToScheduledConfigResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		percentage  int32
+		selector    string
+		wantRollout *response.RolloutPolicy
+	}{
+		{
+			name:       "whole namespace",
+			percentage: 100,
+			selector:   `{}`,
+		},
+		{
+			name:        "canary by percentage",
+			percentage:  10,
+			selector:    `{}`,
+			wantRollout: &response.RolloutPolicy{Percentage: 10},
+		},
+		{
+			name:        "canary by selector",
+			percentage:  100,
+			selector:    `{"region":"eu"}`,
+			wantRollout: &response.RolloutPolicy{Percentage: 100, Selector: map[string]string{"region": "eu"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := toScheduledConfigResponse(queries.ScheduledConfig{
+				ID:                uuid.New(),
+				Namespace:         "default",
+				Config:            json.RawMessage(`{"url":"https://night"}`),
+				RolloutPercentage: tt.percentage,
+				RolloutSelector:   json.RawMessage(tt.selector),
+				EffectiveAt:       time.Now().Add(time.Hour),
+			})
+
+			if resp.Status != response.ConfigStatusScheduled {
+				t.Errorf("Status = %q, want %q", resp.Status, response.ConfigStatusScheduled)
+			}
+			got, _ := json.Marshal(resp.Rollout)
+			want, _ := json.Marshal(tt.wantRollout)
+			if string(got) != string(want) {
+				t.Errorf("Rollout = %s, want %s", got, want)
+			}
+		})
+	}
+}
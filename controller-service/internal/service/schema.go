@@ -0,0 +1,203 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"controller-service/internal/api/response"
+	"controller-service/internal/repository"
+	queries "controller-service/internal/repository/sqlc"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// baseConfigSchema holds the fields every config document needs, whatever
+// the namespace schema says: agents read the poll interval and workers the
+// target url.
+var baseConfigSchema = jsonschema.MustCompileString("mem:///base-config.json", `{
+	"type": "object",
+	"properties": {
+		"url": {"type": "string", "minLength": 1},
+		"poll_interval": {"type": "integer", "minimum": 1}
+	},
+	"required": ["url", "poll_interval"]
+}`)
+
+func (s *ControllerService) GetConfigSchema(ctx context.Context, namespace string) (*response.ConfigSchemaResponse, error) {
+	schema, err := s.Repo.GetConfigSchema(ctx, namespace)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("GetConfigSchema Failed to fetch config schema", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	return toConfigSchemaResponse(schema), nil
+}
+
+// SetConfigSchema replaces the JSON Schema that new config versions of the
+// namespace are validated against, or proposes to in namespaces requiring
+// approval. Existing versions are not re-validated.
+func (s *ControllerService) SetConfigSchema(ctx context.Context, namespace string, schema json.RawMessage) (*response.ConfigSchemaResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("SetConfigSchema Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	var resp *response.ConfigSchemaResponse
+	if s.approvalRequired(namespace) {
+		resp, err = s.proposeConfigSchema(ctx, queryTx, namespace, schema)
+	} else {
+		resp, err = setConfigSchema(ctx, queryTx, namespace, schema)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("SetConfigSchema Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// setConfigSchema replaces the schema of the namespace through queryTx,
+// leaving the commit to the caller.
+func setConfigSchema(ctx context.Context, queryTx *queries.Queries, namespace string, schema json.RawMessage) (*response.ConfigSchemaResponse, error) {
+	if _, err := compileSchema(schema); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSchema, err)
+	}
+
+	var previous *response.ConfigSchemaResponse
+	previousSchema, err := queryTx.GetConfigSchema(ctx, namespace)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		slog.Error("setConfigSchema Failed to fetch config schema", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	default:
+		previous = toConfigSchemaResponse(previousSchema)
+	}
+
+	configSchema, err := queryTx.UpsertConfigSchema(ctx, queries.UpsertConfigSchemaParams{
+		Namespace: namespace,
+		Schema:    schema,
+	})
+	if err != nil {
+		slog.Error("setConfigSchema Failed to upsert config schema", slog.Any("error", err), slog.String("namespace", namespace))
+		return nil, err
+	}
+
+	resp := toConfigSchemaResponse(configSchema)
+	if err := recordAudit(ctx, queryTx, response.AuditActionConfigSchemaSet, configResource(namespace)+"/schema", previous, resp); err != nil {
+		slog.Error("setConfigSchema Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// validateConfig checks a config document against the base schema and the
+// schema registered for the namespace, if any.
+func validateConfig(ctx context.Context, repo repository.IRepository, namespace string, config json.RawMessage) error {
+	document, err := decodeDocument(config)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, err)
+	}
+
+	if err := baseConfigSchema.Validate(document); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, validationMessage(err))
+	}
+
+	configSchema, err := repo.GetConfigSchema(ctx, namespace)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		slog.Error("validateConfig Failed to fetch config schema", slog.Any("error", err), slog.String("namespace", namespace))
+		return err
+	}
+
+	schema, err := compileSchema(configSchema.Schema)
+	if err != nil {
+		slog.Error("validateConfig Failed to compile config schema", slog.Any("error", err), slog.String("namespace", namespace))
+		return err
+	}
+
+	if err := schema.Validate(document); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, validationMessage(err))
+	}
+
+	return nil
+}
+
+func compileSchema(schema json.RawMessage) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	// schemas are self-contained, $ref must not reach the file system or network
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("loading %s is not allowed", url)
+	}
+
+	if err := compiler.AddResource("mem:///config-schema.json", bytes.NewReader(schema)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile("mem:///config-schema.json")
+}
+
+// decodeDocument decodes JSON keeping numbers as json.Number, so integers
+// survive validation and re-encoding unchanged.
+func decodeDocument(data json.RawMessage) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// validationMessage flattens a schema validation error into a single line
+// listing every failing keyword with its location in the document.
+func validationMessage(err error) string {
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err.Error()
+	}
+
+	var messages []string
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == "" || strings.HasPrefix(unit.Error, "doesn't validate with") {
+			continue
+		}
+		location := unit.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", location, unit.Error))
+	}
+	if len(messages) == 0 {
+		return validationErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func toConfigSchemaResponse(schema queries.ConfigSchema) *response.ConfigSchemaResponse {
+	return &response.ConfigSchemaResponse{
+		Namespace: schema.Namespace,
+		Schema:    schema.Schema,
+		UpdatedAt: schema.UpdatedAt,
+	}
+}
@@ -0,0 +1,511 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"controller-service/internal/api/request"
+	"controller-service/internal/api/response"
+	"controller-service/internal/repository"
+	queries "controller-service/internal/repository/sqlc"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// webhookSecretPrefix marks webhook signing secrets issued by the
+	// controller.
+	webhookSecretPrefix = "whsec_"
+
+	// webhookBatchSize is how many due deliveries a dispatcher claims at once.
+	webhookBatchSize = 20
+	// webhookTimeout bounds a single delivery attempt.
+	webhookTimeout = 10 * time.Second
+	// webhookLease keeps claimed deliveries from being claimed again while
+	// they are attempted, so it must exceed webhookTimeout.
+	webhookLease = time.Minute
+
+	// webhookMaxAttempts is how often a delivery is attempted before it is
+	// marked failed; the wait between attempts doubles from webhookBackoffBase
+	// up to webhookBackoffMax.
+	webhookMaxAttempts = 10
+	webhookBackoffBase = 10 * time.Second
+	webhookBackoffMax  = time.Hour
+
+	// webhookMaxErrorLength truncates the error stored with a failed attempt.
+	webhookMaxErrorLength = 500
+)
+
+// CreateWebhook subscribes a URL to config change events. The secret that
+// deliveries are signed with is generated here and only returned once.
+func (s *ControllerService) CreateWebhook(ctx context.Context, payload request.WebhookRequest) (*response.CreatedWebhookResponse, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		slog.Error("CreateWebhook Failed to generate secret", slog.Any("error", err))
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("CreateWebhook Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	webhook, err := queryTx.CreateWebhook(ctx, queries.CreateWebhookParams{
+		Url:        payload.URL,
+		Secret:     webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(secret),
+		Namespaces: webhookNamespaces(payload.Namespaces),
+		Enabled:    payload.IsEnabled(),
+	})
+	if err != nil {
+		slog.Error("CreateWebhook Failed to create webhook", slog.Any("error", err))
+		return nil, err
+	}
+
+	resp := toWebhookResponse(webhook)
+	if err := recordAudit(ctx, queryTx, response.AuditActionWebhookCreate, webhookResource(webhook.ID), nil, resp); err != nil {
+		slog.Error("CreateWebhook Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("CreateWebhook Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &response.CreatedWebhookResponse{
+		WebhookResponse: resp,
+		Secret:          webhook.Secret,
+	}, nil
+}
+
+func (s *ControllerService) ListWebhooks(ctx context.Context) (*response.WebhookListResponse, error) {
+	webhooks, err := s.Repo.ListWebhooks(ctx)
+	if err != nil {
+		slog.Error("ListWebhooks Failed to list webhooks", slog.Any("error", err))
+		return nil, err
+	}
+
+	items := make([]response.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		items = append(items, toWebhookResponse(webhook))
+	}
+
+	return &response.WebhookListResponse{Items: items}, nil
+}
+
+func (s *ControllerService) GetWebhook(ctx context.Context, id uuid.UUID) (*response.WebhookResponse, error) {
+	webhook, err := s.Repo.GetWebhook(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("GetWebhook Failed to fetch webhook", slog.Any("error", err), slog.String("webhook_id", id.String()))
+		return nil, err
+	}
+
+	resp := toWebhookResponse(webhook)
+	return &resp, nil
+}
+
+// UpdateWebhook replaces the URL, namespaces and enabled flag of a webhook.
+// Pending deliveries go to the new URL.
+func (s *ControllerService) UpdateWebhook(ctx context.Context, id uuid.UUID, payload request.WebhookRequest) (*response.WebhookResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("UpdateWebhook Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	previous, err := queryTx.GetWebhook(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("UpdateWebhook Failed to fetch webhook", slog.Any("error", err), slog.String("webhook_id", id.String()))
+		return nil, err
+	}
+
+	webhook, err := queryTx.UpdateWebhook(ctx, queries.UpdateWebhookParams{
+		ID:         id,
+		Url:        payload.URL,
+		Namespaces: webhookNamespaces(payload.Namespaces),
+		Enabled:    payload.IsEnabled(),
+	})
+	if err != nil {
+		slog.Error("UpdateWebhook Failed to update webhook", slog.Any("error", err), slog.String("webhook_id", id.String()))
+		return nil, err
+	}
+
+	resp := toWebhookResponse(webhook)
+	if err := recordAudit(ctx, queryTx, response.AuditActionWebhookUpdate, webhookResource(id), toWebhookResponse(previous), resp); err != nil {
+		slog.Error("UpdateWebhook Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("UpdateWebhook Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// DeleteWebhook removes a webhook together with its deliveries.
+func (s *ControllerService) DeleteWebhook(ctx context.Context, id uuid.UUID) (*response.WebhookResponse, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("DeleteWebhook Failed to begin transaction", slog.Any("error", err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryTx := s.Repo.WithTx(tx)
+
+	webhook, err := queryTx.DeleteWebhook(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.Error("DeleteWebhook Failed to delete webhook", slog.Any("error", err), slog.String("webhook_id", id.String()))
+		return nil, err
+	}
+
+	resp := toWebhookResponse(webhook)
+	if err := recordAudit(ctx, queryTx, response.AuditActionWebhookDelete, webhookResource(id), resp, nil); err != nil {
+		slog.Error("DeleteWebhook Failed to record audit event", slog.Any("error", err))
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("DeleteWebhook Failed to commit transaction", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (s *ControllerService) ListWebhookDeliveries(ctx context.Context, id uuid.UUID, filter request.WebhookDeliveryFilter, pagination request.PaginationRequest) (*response.WebhookDeliveryListResponse, error) {
+	if _, err := s.GetWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+
+	status := sql.NullString{String: filter.Status, Valid: filter.Status != ""}
+
+	deliveries, err := s.Repo.ListWebhookDeliveries(ctx, queries.ListWebhookDeliveriesParams{
+		WebhookID: id,
+		Status:    status,
+		Limit:     int32(pagination.PageSize),
+		Offset:    int32(pagination.Offset()),
+	})
+	if err != nil {
+		slog.Error("ListWebhookDeliveries Failed to list webhook deliveries", slog.Any("error", err))
+		return nil, err
+	}
+
+	total, err := s.Repo.CountWebhookDeliveries(ctx, queries.CountWebhookDeliveriesParams{
+		WebhookID: id,
+		Status:    status,
+	})
+	if err != nil {
+		slog.Error("ListWebhookDeliveries Failed to count webhook deliveries", slog.Any("error", err))
+		return nil, err
+	}
+
+	items := make([]response.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		items = append(items, toWebhookDeliveryResponse(delivery))
+	}
+
+	return &response.WebhookDeliveryListResponse{
+		Items:    items,
+		Page:     pagination.Page,
+		PageSize: pagination.PageSize,
+		Total:    total,
+	}, nil
+}
+
+// enqueueConfigEvent queues a webhook event for a new config version with
+// every enabled webhook subscribed to its namespace. Like recordAudit, repo
+// should be the transaction making the change, so the event is only
+// delivered once the change commits.
+func enqueueConfigEvent(ctx context.Context, repo repository.IRepository, eventType string, previous *queries.GlobalConfig, config queries.GlobalConfig) error {
+	from := json.RawMessage("{}")
+	var previousVersion int64
+	if previous != nil {
+		from = previous.Config
+		previousVersion = previous.Version
+	}
+
+	diff, err := diffConfig(from, config.Config)
+	if err != nil {
+		return err
+	}
+
+	eventID := uuid.New()
+	payload, err := json.Marshal(response.WebhookEvent{
+		ID:              eventID.String(),
+		Type:            eventType,
+		Namespace:       config.Namespace,
+		Version:         config.Version,
+		PreviousVersion: previousVersion,
+		Status:          config.Status,
+		Actor:           auditActor(ctx),
+		Diff:            diff,
+		CreatedAt:       config.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.EnqueueWebhookDeliveries(ctx, queries.EnqueueWebhookDeliveriesParams{
+		EventID:   eventID,
+		EventType: eventType,
+		Payload:   payload,
+		Namespace: config.Namespace,
+	})
+	return err
+}
+
+// enqueueAbortEvent queues the config.abort event of a canary aborted by an
+// update or rollback that wrote no new version, as an abort through the
+// rollout endpoint would: the aborted canary diffed against the active
+// version its agents go back to. canary is the version served before the
+// abort; nothing is queued when it was not a canary.
+func enqueueAbortEvent(ctx context.Context, repo repository.IRepository, active, canary *queries.GlobalConfig) error {
+	if canary == nil || canary.Status != response.ConfigStatusCanary {
+		return nil
+	}
+
+	aborted := *canary
+	aborted.Status = response.ConfigStatusAborted
+	return enqueueConfigEvent(ctx, repo, response.AuditActionConfigAbort, active, aborted)
+}
+
+// RunWebhookDispatcher delivers due webhook events, checking once per
+// interval until ctx is done. Deliveries are claimed with SKIP LOCKED, so
+// several controllers can dispatch from the same database.
+func (s *ControllerService) RunWebhookDispatcher(ctx context.Context, interval time.Duration) {
+	client := &http.Client{Timeout: webhookTimeout}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// drain the backlog before waiting for the next tick
+		for {
+			claimed, err := s.dispatchWebhooks(ctx, client)
+			if err != nil {
+				slog.Error("RunWebhookDispatcher Failed to claim webhook deliveries", slog.Any("error", err))
+				break
+			}
+			if claimed < webhookBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchWebhooks attempts a batch of due deliveries and returns how many
+// were claimed. Namespaces are delivered concurrently, the events of each
+// namespace one after another in the order they were enqueued, so receivers
+// see a namespace's changes in order.
+func (s *ControllerService) dispatchWebhooks(ctx context.Context, client *http.Client) (int, error) {
+	deliveries, err := s.Repo.ClaimWebhookDeliveries(ctx, queries.ClaimWebhookDeliveriesParams{
+		LeaseSeconds: int32(webhookLease / time.Second),
+		Limit:        webhookBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	slices.SortFunc(deliveries, func(a, b queries.ClaimWebhookDeliveriesRow) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	byNamespace := make(map[string][]queries.ClaimWebhookDeliveriesRow)
+	for _, delivery := range deliveries {
+		byNamespace[delivery.Namespace] = append(byNamespace[delivery.Namespace], delivery)
+	}
+
+	var wg sync.WaitGroup
+	for _, namespaceDeliveries := range byNamespace {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, delivery := range namespaceDeliveries {
+				s.deliverWebhook(ctx, client, delivery)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// deliverWebhook attempts a claimed delivery and records the outcome: the
+// delivery is done, retried after a backoff, or failed for good once it ran
+// out of attempts.
+func (s *ControllerService) deliverWebhook(ctx context.Context, client *http.Client, delivery queries.ClaimWebhookDeliveriesRow) {
+	statusCode, err := sendWebhook(ctx, client, delivery)
+	lastStatusCode := sql.NullInt32{Int32: int32(statusCode), Valid: statusCode != 0}
+
+	if err == nil {
+		if err := s.Repo.MarkWebhookDeliveryDelivered(ctx, queries.MarkWebhookDeliveryDeliveredParams{
+			ID:             delivery.ID,
+			LastStatusCode: lastStatusCode,
+		}); err != nil {
+			slog.Error("deliverWebhook Failed to mark webhook delivery delivered", slog.Any("error", err), slog.Int64("delivery_id", delivery.ID))
+		}
+		return
+	}
+
+	status := response.WebhookDeliveryStatusPending
+	retryAfter := webhookBackoff(delivery.Attempts)
+	if delivery.Attempts >= webhookMaxAttempts {
+		status = response.WebhookDeliveryStatusFailed
+		retryAfter = 0
+	}
+
+	lastError := err.Error()
+	if len(lastError) > webhookMaxErrorLength {
+		lastError = lastError[:webhookMaxErrorLength]
+	}
+
+	slog.Warn("deliverWebhook Failed to deliver webhook event", slog.Any("error", err), slog.Int64("delivery_id", delivery.ID), slog.Int("attempts", int(delivery.Attempts)), slog.String("status", status))
+
+	if err := s.Repo.MarkWebhookDeliveryFailed(ctx, queries.MarkWebhookDeliveryFailedParams{
+		Status:            status,
+		RetryAfterSeconds: int32(retryAfter / time.Second),
+		LastStatusCode:    lastStatusCode,
+		LastError:         lastError,
+		ID:                delivery.ID,
+	}); err != nil {
+		slog.Error("deliverWebhook Failed to mark webhook delivery failed", slog.Any("error", err), slog.Int64("delivery_id", delivery.ID))
+	}
+}
+
+// sendWebhook posts the event of a delivery, signed with the webhook secret,
+// and returns the response status code, or 0 when there was no response. Any
+// 2xx response counts as delivered.
+func sendWebhook(ctx context.Context, client *http.Client, delivery queries.ClaimWebhookDeliveriesRow) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-DCM-Event", delivery.EventType)
+	req.Header.Set("X-DCM-Event-ID", delivery.EventID.String())
+	req.Header.Set("X-DCM-Delivery", strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set("X-DCM-Timestamp", timestamp)
+	req.Header.Set("X-DCM-Signature", "sha256="+signWebhook(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// signWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>". Covering
+// the timestamp lets receivers reject replayed deliveries.
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff is the wait after the given number of failed attempts.
+func webhookBackoff(attempts int32) time.Duration {
+	backoff := webhookBackoffBase
+	for i := int32(1); i < attempts && backoff < webhookBackoffMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookBackoffMax)
+}
+
+// webhookNamespaces keeps an omitted namespaces list from being stored as
+// NULL.
+func webhookNamespaces(namespaces []string) []string {
+	if namespaces == nil {
+		return []string{}
+	}
+	return namespaces
+}
+
+func webhookResource(id uuid.UUID) string {
+	return "webhooks/" + id.String()
+}
+
+func toWebhookResponse(webhook queries.Webhook) response.WebhookResponse {
+	return response.WebhookResponse{
+		ID:         webhook.ID.String(),
+		URL:        webhook.Url,
+		Namespaces: webhookNamespaces(webhook.Namespaces),
+		Enabled:    webhook.Enabled,
+		CreatedAt:  webhook.CreatedAt,
+		UpdatedAt:  webhook.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery queries.WebhookDelivery) response.WebhookDeliveryResponse {
+	resp := response.WebhookDeliveryResponse{
+		ID:        delivery.ID,
+		WebhookID: delivery.WebhookID.String(),
+		EventID:   delivery.EventID.String(),
+		EventType: delivery.EventType,
+		Payload:   delivery.Payload,
+		Status:    delivery.Status,
+		Attempts:  delivery.Attempts,
+		LastError: delivery.LastError,
+		CreatedAt: delivery.CreatedAt,
+	}
+	if delivery.Status == response.WebhookDeliveryStatusPending {
+		resp.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	if delivery.LastStatusCode.Valid {
+		resp.LastStatusCode = &delivery.LastStatusCode.Int32
+	}
+	if delivery.DeliveredAt.Valid {
+		resp.DeliveredAt = &delivery.DeliveredAt.Time
+	}
+	return resp
+}
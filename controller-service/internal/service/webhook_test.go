@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"controller-service/internal/api/response"
+	mock_repository "controller-service/internal/repository/mocks"
+	queries "controller-service/internal/repository/sqlc"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+// verifyWebhookSignature checks a delivery the way the README tells
+// receivers to.
+func verifyWebhookSignature(secret, timestamp string, body []byte, header string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(header), []byte(want))
+}
+
+func TestSendWebhookSignature(t *testing.T) {
+	const secret = "whsec_test"
+	payload := []byte(`{"id":"1","type":"config.update","namespace":"default","version":2}`)
+
+	var (
+		timestamp string
+		signature string
+		body      []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamp = r.Header.Get("X-DCM-Timestamp")
+		signature = r.Header.Get("X-DCM-Signature")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	status, err := sendWebhook(context.Background(), server.Client(), queries.ClaimWebhookDeliveriesRow{
+		ID:        1,
+		EventID:   uuid.New(),
+		EventType: response.AuditActionConfigUpdate,
+		Payload:   payload,
+		Url:       server.URL,
+		Secret:    secret,
+	})
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("sendWebhook() = %d, %v, want %d", status, err, http.StatusOK)
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		want      bool
+	}{
+		{name: "delivery as sent", secret: secret, timestamp: timestamp, body: body, want: true},
+		{name: "tampered body", secret: secret, timestamp: timestamp, body: []byte(strings.Replace(string(body), `"version":2`, `"version":3`, 1))},
+		{name: "replayed with another timestamp", secret: secret, timestamp: timestamp + "0", body: body},
+		{name: "other secret", secret: "whsec_other", timestamp: timestamp, body: body},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyWebhookSignature(tt.secret, tt.timestamp, tt.body, signature); got != tt.want {
+				t.Errorf("signature verifies = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnqueueConfigEventAbort(t *testing.T) {
+	active := queries.GlobalConfig{
+		Namespace: "default",
+		Config:    json.RawMessage(`{"url":"https://v5"}`),
+		Version:   5,
+		Status:    response.ConfigStatusActive,
+	}
+	aborted := queries.GlobalConfig{
+		Namespace: "default",
+		Config:    json.RawMessage(`{"url":"https://v6"}`),
+		Version:   6,
+		Status:    response.ConfigStatusAborted,
+	}
+
+	ctrl := gomock.NewController(t)
+	repo := mock_repository.NewMockIRepository(ctrl)
+	repo.EXPECT().
+		EnqueueWebhookDeliveries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg queries.EnqueueWebhookDeliveriesParams) (int64, error) {
+			if arg.EventType != response.AuditActionConfigAbort || arg.Namespace != "default" {
+				t.Errorf("EnqueueWebhookDeliveries() type %q in %q, want %q in %q", arg.EventType, arg.Namespace, response.AuditActionConfigAbort, "default")
+			}
+
+			var event response.WebhookEvent
+			if err := json.Unmarshal(arg.Payload, &event); err != nil {
+				t.Fatalf("payload is not an event: %v", err)
+			}
+			if event.Version != 6 || event.PreviousVersion != 5 || event.Status != response.ConfigStatusAborted {
+				t.Errorf("event = version %d from %d %s, want version 6 from 5 aborted", event.Version, event.PreviousVersion, event.Status)
+			}
+			if len(event.Diff) != 1 || event.Diff[0].Field != "url" {
+				t.Errorf("event diff = %+v, want the url change", event.Diff)
+			}
+			return 1, nil
+		})
+
+	if err := enqueueConfigEvent(context.Background(), repo, response.AuditActionConfigAbort, &active, aborted); err != nil {
+		t.Fatalf("enqueueConfigEvent() error = %v", err)
+	}
+}
+
+func TestEnqueueAbortEventWithoutCanary(t *testing.T) {
+	active := queries.GlobalConfig{
+		Namespace: "default",
+		Config:    json.RawMessage(`{"url":"https://v5"}`),
+		Version:   5,
+		Status:    response.ConfigStatusActive,
+	}
+
+	// the mock fails the test on any call
+	ctrl := gomock.NewController(t)
+	repo := mock_repository.NewMockIRepository(ctrl)
+
+	for _, previous := range []*queries.GlobalConfig{nil, &active} {
+		if err := enqueueAbortEvent(context.Background(), repo, &active, previous); err != nil {
+			t.Fatalf("enqueueAbortEvent() error = %v", err)
+		}
+	}
+}
+
+func TestDispatchWebhooksInOrderPerNamespace(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight = make(map[string]bool)
+		received = make(map[string][]int64)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace := r.URL.Query().Get("namespace")
+		id, _ := strconv.ParseInt(r.Header.Get("X-DCM-Delivery"), 10, 64)
+
+		mu.Lock()
+		if inFlight[namespace] {
+			t.Errorf("delivery %d of %q sent while another of the namespace is in flight", id, namespace)
+		}
+		inFlight[namespace] = true
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight[namespace] = false
+		received[namespace] = append(received[namespace], id)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	delivery := func(id int64, namespace string) queries.ClaimWebhookDeliveriesRow {
+		return queries.ClaimWebhookDeliveriesRow{
+			ID:        id,
+			EventID:   uuid.New(),
+			EventType: response.AuditActionConfigUpdate,
+			Payload:   json.RawMessage(`{}`),
+			Url:       server.URL + "?namespace=" + namespace,
+			Namespace: namespace,
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	repo := mock_repository.NewMockIRepository(ctrl)
+	// claimed rows come back in no particular order
+	repo.EXPECT().
+		ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Return([]queries.ClaimWebhookDeliveriesRow{
+			delivery(3, "default"),
+			delivery(2, "staging"),
+			delivery(1, "default"),
+			delivery(4, "staging"),
+			delivery(5, "default"),
+		}, nil)
+	repo.EXPECT().MarkWebhookDeliveryDelivered(gomock.Any(), gomock.Any()).Return(nil).Times(5)
+
+	s := &ControllerService{Repo: repo}
+	claimed, err := s.dispatchWebhooks(context.Background(), server.Client())
+	if err != nil || claimed != 5 {
+		t.Fatalf("dispatchWebhooks() = %d, %v, want 5", claimed, err)
+	}
+
+	want := map[string][]int64{"default": {1, 3, 5}, "staging": {2, 4}}
+	if !reflect.DeepEqual(received, want) {
+		t.Errorf("received = %v, want %v", received, want)
+	}
+}
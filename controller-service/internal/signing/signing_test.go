@@ -0,0 +1,62 @@
+package signing
+
+import "testing"
+
+// The worker verifies the same vector in its signing tests, so a change to
+// the signed message on one side only fails there.
+const (
+	testSeed      = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+	testAgentID   = "6f1c2d3e-0000-4000-8000-000000000001"
+	testConfig    = `{"url": "https://example.com", "timeout_seconds": 10, "headers": {"X-Token": "t"}}`
+	testSignature = "gSqHQsEIS7IhY70BYhzdfSV06D5ZHupk1fgsmgtsyhSIEVCvXZVX/OI5KVWqBfWt5LbbzHCRiUIIUlgh7eg7Dw=="
+)
+
+func TestSign(t *testing.T) {
+	signer, err := LoadSigner("", testSeed)
+	if err != nil {
+		t.Fatalf("LoadSigner() error = %v", err)
+	}
+
+	signature, err := signer.Sign(42, "default", testAgentID, []byte(testConfig))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if signature != testSignature {
+		t.Errorf("Sign() = %q, want %q", signature, testSignature)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	want := `42.default.` + testAgentID + `.{"headers":{"X-Token":"t"},"timeout_seconds":10,"url":"https://example.com"}`
+
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{name: "as served", config: testConfig},
+		{name: "re-encoded", config: "{\n  \"timeout_seconds\": 10,\n  \"url\": \"https://example.com\",\n  \"headers\": {\"X-Token\": \"t\"}\n}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := Message(42, "default", testAgentID, []byte(tt.config))
+			if err != nil {
+				t.Fatalf("Message() error = %v", err)
+			}
+			if string(message) != want {
+				t.Errorf("Message() = %s, want %s", message, want)
+			}
+		})
+	}
+}
+
+func TestMessageKeepsLargeNumbers(t *testing.T) {
+	// float64 would round it to 9007199254740992
+	message, err := Message(1, "default", "", []byte(`{"n":9007199254740993}`))
+	if err != nil {
+		t.Fatalf("Message() error = %v", err)
+	}
+	if want := `1.default..{"n":9007199254740993}`; string(message) != want {
+		t.Errorf("Message() = %s, want %s", message, want)
+	}
+}
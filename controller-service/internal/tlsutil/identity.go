@@ -0,0 +1,18 @@
+package tlsutil
+
+import "crypto/x509"
+
+// CertificateIdentity names the holder of a certificate: its common name,
+// or its first DNS or URI subject alternative name when the CN is empty.
+func CertificateIdentity(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package tlsutil
+
+import (
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// reloadCheckInterval bounds how often the watched files are stat'ed, so a
+// busy server does not hit the file system on every handshake.
+const reloadCheckInterval = time.Second
+
+// reloader holds a value loaded from files and loads it again when one of
+// the files changes on disk. A failed reload keeps the previous value, so a
+// half-written certificate does not take the service down.
+type reloader[T any] struct {
+	files []string
+	load  func() (T, error)
+
+	mu        sync.Mutex
+	value     T
+	modTimes  []time.Time
+	checkedAt time.Time
+}
+
+func newReloader[T any](load func() (T, error), files ...string) (*reloader[T], error) {
+	r := &reloader[T]{files: files, load: load}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	r.value = value
+	r.modTimes = r.stat()
+	r.checkedAt = time.Now()
+
+	return r, nil
+}
+
+func (r *reloader[T]) get() T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < reloadCheckInterval {
+		return r.value
+	}
+	r.checkedAt = time.Now()
+
+	modTimes := r.stat()
+	if equalTimes(modTimes, r.modTimes) {
+		return r.value
+	}
+
+	value, err := r.load()
+	if err != nil {
+		slog.Error("tlsutil Failed to reload, keeping the previous version", slog.Any("error", err), slog.Any("files", r.files))
+		return r.value
+	}
+
+	slog.Info("tlsutil reloaded", slog.Any("files", r.files))
+	r.value = value
+	r.modTimes = modTimes
+	return r.value
+}
+
+func (r *reloader[T]) stat() []time.Time {
+	modTimes := make([]time.Time, len(r.files))
+	for i, file := range r.files {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[i] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+func equalTimes(a, b []time.Time) bool {
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
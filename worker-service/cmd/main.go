@@ -8,11 +8,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 	"worker-service/internal/api/handler"
 	"worker-service/internal/api/middleware"
 	"worker-service/internal/config"
+	"worker-service/internal/signing"
+	"worker-service/internal/tlsutil"
 
 	_ "worker-service/docs"
 
@@ -26,19 +34,61 @@ func main() {
 	}
 
 	cfg := config.Load()
-	srv := handler.New()
+
+	verifier, err := signing.LoadVerifier(cfg.ConfigVerifyKeyFile, cfg.ConfigVerifyKey)
+	if err != nil {
+		slog.Error("Failed to load config verify key", slog.Any("error", err))
+		panic(err)
+	}
+	if verifier == nil {
+		slog.Warn("CONFIG_VERIFY_KEY_FILE and CONFIG_VERIFY_KEY not set, config signatures are not verified")
+	}
+
+	srv := handler.New(verifier, cfg.ConfigNamespace, cfg.ConfigAgentID)
 
 	mux := http.NewServeMux()
 	auth := middleware.APIKeyAuth(cfg.APIKey)
 
+	mux.Handle("GET /config", auth(http.HandlerFunc(srv.GetConfig)))
 	mux.Handle("POST /config", auth(http.HandlerFunc(srv.UpdateConfig)))
 	mux.Handle("GET /hit", auth(http.HandlerFunc(srv.Hit)))
+	mux.HandleFunc("GET /health", srv.Health)
 
 	mux.Handle("/docs/", httpSwagger.WrapHandler)
 
-	slog.Info("Starting server at :" + cfg.AppPort)
-	if err := http.ListenAndServeTLS(":"+cfg.AppPort, cfg.TLSCertFile, cfg.TLSKeyFile, mux); err != nil {
-		slog.Error("ListenAndServe: ", slog.Any("error", err))
+	tlsConfig, err := tlsutil.ServerConfig(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile, cfg.TLSClientAuth)
+	if err != nil {
+		slog.Error("Failed to configure TLS", slog.Any("error", err))
 		panic(err)
 	}
+
+	server := &http.Server{
+		Addr:      ":" + cfg.AppPort,
+		Handler:   mux,
+		TLSConfig: tlsConfig,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("Starting server at :" + cfg.AppPort)
+		// certificates come from tlsConfig, reloaded when they change on disk
+		if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("ListenAndServe: ", slog.Any("error", err))
+			panic(err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // a second signal kills the process
+	slog.Info("Shutting down, draining in-flight requests", slog.Int("timeout_seconds", cfg.ShutdownTimeoutSeconds))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeoutSeconds)*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Failed to drain server", slog.Any("error", err))
+	}
+	slog.Info("Shutdown complete")
 }
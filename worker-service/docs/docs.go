@@ -16,13 +16,36 @@ const docTemplate = `{
     "basePath": "{{.BasePath}}",
     "paths": {
         "/config": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the config document the worker applies and its version, 0 when the config was sent without one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "summary": "Get worker config",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConfigResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Set the URL the worker should hit",
+                "description": "Set the URL the worker should hit, with optional request headers and timeout. With a verify key configured, the config must carry the controller's signature of it, its version, namespace and agent. A version older than the applied one is rejected when the push carries X-Config-Version or a verify key is configured.",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,6 +57,30 @@ const docTemplate = `{
                 ],
                 "summary": "Update worker config",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Config version, as served by the controller",
+                        "name": "X-Config-Version",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Namespace the config was served in",
+                        "name": "X-Config-Namespace",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent the config was served to",
+                        "name": "X-Config-Agent-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Controller signature of the version, namespace, agent and config",
+                        "name": "X-Config-Signature",
+                        "in": "header"
+                    },
                     {
                         "description": "Worker config",
                         "name": "body",
@@ -54,6 +101,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -63,6 +122,26 @@ const docTemplate = `{
                 }
             }
         },
+        "/health": {
+            "get": {
+                "description": "Reports that the worker is up and whether it has a target URL configured",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Worker health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/hit": {
             "get": {
                 "security": [
@@ -102,9 +181,40 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "handler.ConfigResponse": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "type": "object"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "configured": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "handler.WorkerConfig": {
             "type": "object",
             "properties": {
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "timeout_seconds": {
+                    "type": "integer"
+                },
                 "url": {
                     "type": "string"
                 }
@@ -2,63 +2,199 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
+	"worker-service/internal/signing"
 )
 
 type WorkerHandler struct {
 	mu     sync.RWMutex
 	config WorkerConfig
+
+	// version and document are the applied config version and document as
+	// sent by the agent, reported back so it can detect drift
+	version  int64
+	document json.RawMessage
+
+	// verifier rejects configs not signed by the controller; nil accepts
+	// unsigned configs
+	verifier *signing.Verifier
+
+	// namespace and agentID are the namespace and, when set, the agent a
+	// signed config must have been served for
+	namespace string
+	agentID   string
 }
 
-// WorkerConfig holds the worker's runtime configuration.
+// defaultHitTimeoutSeconds bounds the request to the target URL when the
+// config does not set timeout_seconds.
+const defaultHitTimeoutSeconds = 30
+
+// WorkerConfig holds the worker's runtime configuration. It is read from the
+// config document relayed by the agent; fields the worker does not know are
+// ignored.
 type WorkerConfig struct {
-	URL string `json:"url"`
+	URL            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+}
+
+// ConfigResponse is the config the worker currently applies. Config is null
+// until a config is received.
+type ConfigResponse struct {
+	Version int64           `json:"version"`
+	Config  json.RawMessage `json:"config" swaggertype:"object"`
+}
+
+// HealthResponse is returned by the health endpoint.
+type HealthResponse struct {
+	Status     string `json:"status"`
+	Configured bool   `json:"configured"`
 }
 
-func New() *WorkerHandler {
+func New(verifier *signing.Verifier, namespace, agentID string) *WorkerHandler {
 	return &WorkerHandler{
-		config: WorkerConfig{},
+		config:    WorkerConfig{},
+		verifier:  verifier,
+		namespace: namespace,
+		agentID:   agentID,
 	}
 }
 
 // UpdateConfig godoc
 // @Summary Update worker config
-// @Description Set the URL the worker should hit
+// @Description Set the URL the worker should hit, with optional request headers and timeout. With a verify key configured, the config must carry the controller's signature of it, its version, namespace and agent. A version older than the applied one is rejected when the push carries X-Config-Version or a verify key is configured.
 // @Tags config
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Param X-Config-Version header int false "Config version, as served by the controller"
+// @Param X-Config-Namespace header string false "Namespace the config was served in"
+// @Param X-Config-Agent-ID header string false "Agent the config was served to"
+// @Param X-Config-Signature header string false "Controller signature of the version, namespace, agent and config"
 // @Param body body WorkerConfig true "Worker config"
 // @Success 200
 // @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 409 {string} string
 // @Failure 500 {string} string
 // @Router /config [post]
 func (s *WorkerHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
 	var cfg WorkerConfig
-	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+	if err := json.Unmarshal(body, &cfg); err != nil {
 		http.Error(w, err.Error(), 500)
 		return
 	}
 
+	var version int64
+	header := r.Header.Get("X-Config-Version")
+	if header != "" {
+		version, err = strconv.ParseInt(header, 10, 64)
+		if err != nil {
+			slog.Error("worker config update failed: invalid X-Config-Version header")
+			http.Error(w, "invalid X-Config-Version header", 400)
+			return
+		}
+	}
+
+	if s.verifier != nil {
+		if err := s.verifyConfig(r, version, body); err != nil {
+			slog.Error("worker config update rejected", slog.Any("error", err))
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	// a config the controller once served, signed or not, must not roll
+	// the worker back; a push without a version, e.g. by hand to a worker
+	// without a verify key, has no order and replaces the applied config
+	if (header != "" || s.verifier != nil) && version < s.version {
+		slog.Error("worker config update rejected: version is older than the applied one", slog.Int64("version", version), slog.Int64("applied_version", s.version))
+		http.Error(w, fmt.Sprintf("config version %d is older than the applied version %d", version, s.version), http.StatusConflict)
+		return
+	}
+
 	if cfg.URL == "" {
 		slog.Error("worker config update failed: url is empty")
 		http.Error(w, "url is empty", 400)
 		return
 	}
 
-	s.config.URL = cfg.URL
+	if cfg.TimeoutSeconds < 0 {
+		slog.Error("worker config update failed: timeout_seconds is negative")
+		http.Error(w, "timeout_seconds must not be negative", 400)
+		return
+	}
+
+	s.config = cfg
+	s.version = version
+	s.document = body
 
-	slog.Info("worker config updated:", slog.Any("config", s.config))
+	// header values may hold credentials, so they are not logged
+	slog.Info("worker config updated:", slog.String("url", s.config.URL), slog.Int("headers", len(s.config.Headers)), slog.Int("timeout_seconds", s.config.TimeoutSeconds))
 
 	w.WriteHeader(http.StatusOK)
 }
 
+// verifyConfig checks the X-Config-Signature header against the config body,
+// its version and the namespace and agent it was served for. The namespace
+// must be the worker's own, so is the agent when the worker is bound to one.
+func (s *WorkerHandler) verifyConfig(r *http.Request, version int64, body []byte) error {
+	signature := r.Header.Get("X-Config-Signature")
+	if signature == "" {
+		return errors.New("config is not signed")
+	}
+
+	if r.Header.Get("X-Config-Version") == "" {
+		return errors.New("config has no version")
+	}
+
+	if namespace := r.Header.Get("X-Config-Namespace"); namespace != s.namespace {
+		return fmt.Errorf("config is for namespace %q, not %q", namespace, s.namespace)
+	}
+
+	agentID := r.Header.Get("X-Config-Agent-ID")
+	if s.agentID != "" && agentID != s.agentID {
+		return fmt.Errorf("config is for agent %q, not %q", agentID, s.agentID)
+	}
+
+	return s.verifier.Verify(version, s.namespace, agentID, body, signature)
+}
+
+// GetConfig godoc
+// @Summary Get worker config
+// @Description Returns the config document the worker applies and its version, 0 when the config was sent without one
+// @Tags config
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} ConfigResponse
+// @Router /config [get]
+func (s *WorkerHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ConfigResponse{
+		Version: s.version,
+		Config:  s.document,
+	})
+}
+
 // Hit godoc
 // @Summary Hit the configured URL
 // @Description Makes a GET request to the configured URL and returns the response body
@@ -70,17 +206,35 @@ func (s *WorkerHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {string} string
 // @Router /hit [get]
 func (s *WorkerHandler) Hit(w http.ResponseWriter, r *http.Request) {
+	// copy the config so a slow target does not block config updates; the
+	// headers map is replaced, never modified, by UpdateConfig
 	s.mu.RLock()
-	defer s.mu.RUnlock()
+	config := s.config
+	s.mu.RUnlock()
 
-	url := s.config.URL
+	url := config.URL
 	if url == "" {
 		slog.Error("worker hit failed: url is empty")
 		http.Error(w, "url is empty", 400)
 		return
 	}
 
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		slog.Error("worker hit failed to create request", slog.Any("error", err))
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	for key, value := range config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	timeoutSeconds := config.TimeoutSeconds
+	if timeoutSeconds == 0 {
+		timeoutSeconds = defaultHitTimeoutSeconds
+	}
+	client := &http.Client{Timeout: time.Duration(timeoutSeconds) * time.Second}
+	resp, err := client.Do(req)
 	if err != nil {
 		slog.Error("worker hit failed to get url", slog.Any("error", err))
 		http.Error(w, err.Error(), 500)
@@ -96,3 +250,21 @@ func (s *WorkerHandler) Hit(w http.ResponseWriter, r *http.Request) {
 	}
 	w.Write(body)
 }
+
+// Health godoc
+// @Summary Worker health
+// @Description Reports that the worker is up and whether it has a target URL configured
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthResponse
+// @Router /health [get]
+func (s *WorkerHandler) Health(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(HealthResponse{
+		Status:     "ok",
+		Configured: s.config.URL != "",
+	})
+}
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"worker-service/internal/signing"
+)
+
+func TestUpdateConfigSigned(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	verifier, err := signing.LoadVerifier("", base64.StdEncoding.EncodeToString(publicKey))
+	if err != nil {
+		t.Fatalf("LoadVerifier() error = %v", err)
+	}
+
+	const agentID = "6f1c2d3e-0000-4000-8000-000000000001"
+	sign := func(version int64, namespace, agentID, config string) string {
+		message, err := signing.Message(version, namespace, agentID, []byte(config))
+		if err != nil {
+			t.Fatalf("Message() error = %v", err)
+		}
+		return base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, message))
+	}
+
+	s := New(verifier, "default", "")
+
+	// the pushes run in order against the same worker
+	pushes := []struct {
+		name        string
+		version     int64
+		namespace   string
+		config      string
+		signature   string
+		wantStatus  int
+		wantVersion int64
+	}{
+		{
+			name:        "signed config",
+			version:     5,
+			namespace:   "default",
+			config:      `{"url":"https://v5"}`,
+			signature:   sign(5, "default", agentID, `{"url":"https://v5"}`),
+			wantStatus:  http.StatusOK,
+			wantVersion: 5,
+		},
+		{
+			name:        "unsigned config",
+			version:     6,
+			namespace:   "default",
+			config:      `{"url":"https://v6"}`,
+			wantStatus:  http.StatusForbidden,
+			wantVersion: 5,
+		},
+		{
+			name:        "config signed for another namespace",
+			version:     6,
+			namespace:   "staging",
+			config:      `{"url":"https://v6"}`,
+			signature:   sign(6, "staging", agentID, `{"url":"https://v6"}`),
+			wantStatus:  http.StatusForbidden,
+			wantVersion: 5,
+		},
+		{
+			name:        "same version again",
+			version:     5,
+			namespace:   "default",
+			config:      `{"url":"https://v5"}`,
+			signature:   sign(5, "default", agentID, `{"url":"https://v5"}`),
+			wantStatus:  http.StatusOK,
+			wantVersion: 5,
+		},
+		{
+			name:        "newer signed config",
+			version:     7,
+			namespace:   "default",
+			config:      `{"url":"https://v7"}`,
+			signature:   sign(7, "default", agentID, `{"url":"https://v7"}`),
+			wantStatus:  http.StatusOK,
+			wantVersion: 7,
+		},
+		{
+			name:        "replay of an older signed config",
+			version:     5,
+			namespace:   "default",
+			config:      `{"url":"https://v5"}`,
+			signature:   sign(5, "default", agentID, `{"url":"https://v5"}`),
+			wantStatus:  http.StatusConflict,
+			wantVersion: 7,
+		},
+	}
+
+	for _, push := range pushes {
+		req := httptest.NewRequest(http.MethodPost, "/config", strings.NewReader(push.config))
+		req.Header.Set("X-Config-Version", strconv.FormatInt(push.version, 10))
+		req.Header.Set("X-Config-Namespace", push.namespace)
+		req.Header.Set("X-Config-Agent-ID", agentID)
+		if push.signature != "" {
+			req.Header.Set("X-Config-Signature", push.signature)
+		}
+
+		rec := httptest.NewRecorder()
+		s.UpdateConfig(rec, req)
+
+		if rec.Code != push.wantStatus {
+			t.Errorf("%s: UpdateConfig() status = %d, want %d", push.name, rec.Code, push.wantStatus)
+		}
+		if s.version != push.wantVersion {
+			t.Errorf("%s: applied version = %d, want %d", push.name, s.version, push.wantVersion)
+		}
+	}
+}
+
+func TestUpdateConfigUnsigned(t *testing.T) {
+	s := New(nil, "default", "")
+
+	// the pushes run in order against the same worker; version 0 means the
+	// push carries no X-Config-Version header
+	pushes := []struct {
+		name        string
+		version     int64
+		config      string
+		wantStatus  int
+		wantVersion int64
+	}{
+		{
+			name:        "versioned config",
+			version:     5,
+			config:      `{"url":"https://v5"}`,
+			wantStatus:  http.StatusOK,
+			wantVersion: 5,
+		},
+		{
+			name:        "older versioned config",
+			version:     4,
+			config:      `{"url":"https://v4"}`,
+			wantStatus:  http.StatusConflict,
+			wantVersion: 5,
+		},
+		{
+			name:        "unversioned config",
+			config:      `{"url":"https://manual"}`,
+			wantStatus:  http.StatusOK,
+			wantVersion: 0,
+		},
+		{
+			name:        "unversioned config again",
+			config:      `{"url":"https://manual-2"}`,
+			wantStatus:  http.StatusOK,
+			wantVersion: 0,
+		},
+	}
+
+	for _, push := range pushes {
+		req := httptest.NewRequest(http.MethodPost, "/config", strings.NewReader(push.config))
+		if push.version != 0 {
+			req.Header.Set("X-Config-Version", strconv.FormatInt(push.version, 10))
+		}
+
+		rec := httptest.NewRecorder()
+		s.UpdateConfig(rec, req)
+
+		if rec.Code != push.wantStatus {
+			t.Errorf("%s: UpdateConfig() status = %d, want %d", push.name, rec.Code, push.wantStatus)
+		}
+		if s.version != push.wantVersion {
+			t.Errorf("%s: applied version = %d, want %d", push.name, s.version, push.wantVersion)
+		}
+	}
+}
+
+func TestUpdateConfigBoundToAgent(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	verifier, err := signing.LoadVerifier("", base64.StdEncoding.EncodeToString(publicKey))
+	if err != nil {
+		t.Fatalf("LoadVerifier() error = %v", err)
+	}
+
+	const (
+		agentID = "6f1c2d3e-0000-4000-8000-000000000001"
+		other   = "6f1c2d3e-0000-4000-8000-000000000002"
+		config  = `{"url":"https://override"}`
+	)
+	message, err := signing.Message(3, "default", other, []byte(config))
+	if err != nil {
+		t.Fatalf("Message() error = %v", err)
+	}
+
+	// a config served to another agent, e.g. with its override, is
+	// validly signed but not meant for this worker
+	req := httptest.NewRequest(http.MethodPost, "/config", strings.NewReader(config))
+	req.Header.Set("X-Config-Version", "3")
+	req.Header.Set("X-Config-Namespace", "default")
+	req.Header.Set("X-Config-Agent-ID", other)
+	req.Header.Set("X-Config-Signature", base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, message)))
+
+	rec := httptest.NewRecorder()
+	New(verifier, "default", agentID).UpdateConfig(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("UpdateConfig() status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestHitDoesNotBlockUpdateConfig(t *testing.T) {
+	hit, release := make(chan struct{}), make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(hit)
+		<-release
+		w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+	defer close(release)
+
+	s := New(nil, "default", "")
+	s.config = WorkerConfig{URL: target.URL}
+
+	go s.Hit(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/hit", nil))
+	<-hit
+
+	// the hit is waiting on the target; an update must still go through
+	updated := make(chan int)
+	go func() {
+		rec := httptest.NewRecorder()
+		s.UpdateConfig(rec, httptest.NewRequest(http.MethodPost, "/config", strings.NewReader(`{"url":"https://next"}`)))
+		updated <- rec.Code
+	}()
+
+	select {
+	case code := <-updated:
+		if code != http.StatusOK {
+			t.Errorf("UpdateConfig() status = %d, want %d", code, http.StatusOK)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("UpdateConfig() blocked by an in-flight Hit")
+	}
+}
@@ -1,12 +1,35 @@
 package config
 
-import "os"
+import (
+	"log/slog"
+	"os"
+	"strconv"
+)
 
 type Config struct {
 	AppPort     string
 	APIKey      string
 	TLSCertFile string
 	TLSKeyFile  string
+
+	// TLSClientCAFile enables client certificate verification; TLSClientAuth
+	// is "require" (default) or "optional".
+	TLSClientCAFile string
+	TLSClientAuth   string
+
+	// ConfigVerifyKeyFile (PEM) or ConfigVerifyKey (base64) holds the
+	// controller's Ed25519 public key; configs must be signed with it
+	ConfigVerifyKeyFile string
+	ConfigVerifyKey     string
+
+	// ConfigNamespace is the namespace, ConfigAgentID optionally the agent,
+	// a signed config must have been served for
+	ConfigNamespace string
+	ConfigAgentID   string
+
+	// ShutdownTimeoutSeconds bounds how long in-flight requests may drain
+	// on SIGTERM; keep it below the termination grace period
+	ShutdownTimeoutSeconds int
 }
 
 func Load() Config {
@@ -15,10 +38,37 @@ func Load() Config {
 		appPort = "8081"
 	}
 
+	configNamespace := os.Getenv("CONFIG_NAMESPACE")
+	if configNamespace == "" {
+		configNamespace = "default"
+	}
+
+	shutdownTimeoutSeconds := 25
+	shutdownTimeoutEnv := os.Getenv("SHUTDOWN_TIMEOUT_SECONDS")
+	if shutdownTimeoutEnv != "" {
+		var err error
+		shutdownTimeoutSeconds, err = strconv.Atoi(shutdownTimeoutEnv)
+		if err != nil || shutdownTimeoutSeconds <= 0 {
+			slog.Info("Invalid SHUTDOWN_TIMEOUT_SECONDS value, using default of 25 seconds", slog.String("SHUTDOWN_TIMEOUT_SECONDS", shutdownTimeoutEnv), slog.Any("error", err))
+			shutdownTimeoutSeconds = 25 // default value if conversion fails
+		}
+	}
+
 	return Config{
 		AppPort:     appPort,
 		APIKey:      os.Getenv("API_KEY"),
 		TLSCertFile: os.Getenv("TLS_CERT_FILE"),
 		TLSKeyFile:  os.Getenv("TLS_KEY_FILE"),
+
+		TLSClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+		TLSClientAuth:   os.Getenv("TLS_CLIENT_AUTH"),
+
+		ConfigVerifyKeyFile: os.Getenv("CONFIG_VERIFY_KEY_FILE"),
+		ConfigVerifyKey:     os.Getenv("CONFIG_VERIFY_KEY"),
+
+		ConfigNamespace: configNamespace,
+		ConfigAgentID:   os.Getenv("CONFIG_AGENT_ID"),
+
+		ShutdownTimeoutSeconds: shutdownTimeoutSeconds,
 	}
 }
@@ -0,0 +1,105 @@
+// Package signing verifies the Ed25519 signatures the controller puts on
+// config versions, so a config altered by the agent or its cache is not
+// applied.
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+var ErrInvalidSignature = errors.New("config signature does not verify")
+
+type Verifier struct {
+	key ed25519.PublicKey
+}
+
+// LoadVerifier reads the controller's public key from keyFile, a PEM encoded
+// PKIX public key, or from key, the base64 encoded 32 byte key. It returns
+// nil when neither is set, in which case configs are applied unverified.
+func LoadVerifier(keyFile, key string) (*Verifier, error) {
+	switch {
+	case keyFile != "":
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no PEM data found in " + keyFile)
+		}
+
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%s is not an Ed25519 public key", keyFile)
+		}
+		return &Verifier{key: publicKey}, nil
+	case key != "":
+		publicKey, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("verify key must be %d bytes", ed25519.PublicKeySize)
+		}
+		return &Verifier{key: ed25519.PublicKey(publicKey)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// Verify checks the base64 encoded signature of a config version served in
+// namespace to the agent agentID and returns ErrInvalidSignature when the
+// controller did not make it for them.
+func (v *Verifier) Verify(version int64, namespace, agentID string, config []byte, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	message, err := Message(version, namespace, agentID, config)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(v.key, message, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Message returns the bytes a config version is signed over: the decimal
+// version, the namespace, the agent ID and the canonical JSON of the config
+// document, separated by dots. It must match the controller's
+// signing.Message.
+func Message(version int64, namespace, agentID string, config []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	canonical, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strconv.FormatInt(version, 10) + "." + namespace + "." + agentID + "."
+	return append([]byte(prefix), canonical...), nil
+}
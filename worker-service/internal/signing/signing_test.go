@@ -0,0 +1,102 @@
+package signing
+
+import (
+	"errors"
+	"testing"
+)
+
+// The vector is signed in the controller's signing tests.
+const (
+	testPublicKey = "A6EHv/POEL4dcN0Y50vAmWfk1jCbpQ1fHdyGZBJVMbg="
+	testAgentID   = "6f1c2d3e-0000-4000-8000-000000000001"
+	testConfig    = `{"url": "https://example.com", "timeout_seconds": 10, "headers": {"X-Token": "t"}}`
+	testSignature = "gSqHQsEIS7IhY70BYhzdfSV06D5ZHupk1fgsmgtsyhSIEVCvXZVX/OI5KVWqBfWt5LbbzHCRiUIIUlgh7eg7Dw=="
+)
+
+func TestVerify(t *testing.T) {
+	verifier, err := LoadVerifier("", testPublicKey)
+	if err != nil {
+		t.Fatalf("LoadVerifier() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		version   int64
+		namespace string
+		agentID   string
+		config    string
+		signature string
+		wantErr   error
+	}{
+		{
+			name:      "signed config",
+			version:   42,
+			namespace: "default",
+			agentID:   testAgentID,
+			config:    testConfig,
+			signature: testSignature,
+		},
+		{
+			name:      "re-encoded config",
+			version:   42,
+			namespace: "default",
+			agentID:   testAgentID,
+			config:    `{"headers":{"X-Token":"t"},"timeout_seconds":10,"url":"https://example.com"}`,
+			signature: testSignature,
+		},
+		{
+			name:      "tampered config",
+			version:   42,
+			namespace: "default",
+			agentID:   testAgentID,
+			config:    `{"url": "https://attacker.example", "timeout_seconds": 10, "headers": {"X-Token": "t"}}`,
+			signature: testSignature,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "other version",
+			version:   41,
+			namespace: "default",
+			agentID:   testAgentID,
+			config:    testConfig,
+			signature: testSignature,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "other namespace",
+			version:   42,
+			namespace: "staging",
+			agentID:   testAgentID,
+			config:    testConfig,
+			signature: testSignature,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "other agent",
+			version:   42,
+			namespace: "default",
+			agentID:   "6f1c2d3e-0000-4000-8000-000000000002",
+			config:    testConfig,
+			signature: testSignature,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "signature not base64",
+			version:   42,
+			namespace: "default",
+			agentID:   testAgentID,
+			config:    testConfig,
+			signature: "not a signature",
+			wantErr:   ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifier.Verify(tt.version, tt.namespace, tt.agentID, []byte(tt.config), tt.signature)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}